@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add code to a generated project",
+}
+
+var addResourceCmd = &cobra.Command{
+	Use:   "resource <name>",
+	Short: "Generate a CRUD resource with its model, controller, routes, validation and tests",
+	Long: `Generate a CRUD resource in an existing project: the model, the controller or
+handlers, the routes, a validation schema for request bodies and tests. The
+language, framework and ORM are read from the project's ` + forge.LockFileName + `, or else
+from go.mod, package.json or requirements.txt, and the routes are registered
+in the project's router. Where the router cannot be found, the lines to add are
+printed instead.
+
+Fields are name:type pairs; the types are ` + strings.Join(forge.FieldTypes, ", ") + `.`,
+	Example: "  backendforger add resource product --fields name:string,price:float,stock:int\n" +
+		"  backendforger add resource order-item --fields quantity:int,note:text --dir ./api --path /api/order-items",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q: use text or json", output)
+		}
+		spec, _ := cmd.Flags().GetString("fields")
+		fields, err := forge.ParseFields(spec)
+		if err != nil {
+			return fmt.Errorf("invalid --fields: %w", err)
+		}
+		res := forge.Resource{Name: args[0], Fields: fields}
+		res.Path, _ = cmd.Flags().GetString("path")
+		dir, _ := cmd.Flags().GetString("dir")
+		var opts forge.AddResourceOptions
+		opts.Force, _ = cmd.Flags().GetBool("force")
+		opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		cmd.SilenceUsage = true
+
+		g := &forge.Generator{Source: templateSource}
+		result, err := g.AddResource(cmd.Context(), dir, res, opts)
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			return writeJSON(result)
+		}
+		printAddResource(result, opts)
+		return nil
+	},
+}
+
+func printAddResource(result *forge.AddResourceResult, opts forge.AddResourceOptions) {
+	verb, created, edited := "Added", "created", "edited"
+	if opts.DryRun {
+		verb, created, edited = "Would add", "would create", "would edit"
+	}
+	spec := result.Spec
+	stack := spec.Framework
+	if spec.ORM != "" {
+		stack += ", " + spec.ORM
+	}
+	fmt.Printf("%s resource '%s' to %s (%s):\n", verb, color.BlueString(result.Resource), result.ProjectPath, stack)
+	for _, f := range result.Files {
+		fmt.Printf("\t%s %s\n", color.GreenString("%-12s", created), f)
+	}
+	var manual []forge.ResourceEdit
+	printed := map[string]bool{}
+	for _, e := range result.Edits {
+		switch {
+		case e.Status == forge.EditApplied && !printed[e.File]:
+			fmt.Printf("\t%s %s\n", color.YellowString("%-12s", edited), e.File)
+			printed[e.File] = true
+		case e.Status == forge.EditManual:
+			manual = append(manual, e)
+		}
+	}
+	fmt.Println()
+
+	if len(manual) > 0 {
+		fmt.Println(color.YellowString("The routes could not be wired in automatically. Add these lines yourself:"))
+		for _, e := range manual {
+			fmt.Printf("  in %s:\n", e.File)
+			for _, line := range strings.Split(e.Snippet, "\n") {
+				fmt.Printf("\t%s\n", color.MagentaString(strings.TrimLeft(line, "\t")))
+			}
+		}
+		fmt.Println()
+	}
+	for _, step := range result.NextSteps {
+		fmt.Println(step.Description)
+		for _, c := range step.Commands {
+			fmt.Printf("\t%s\n", color.MagentaString(c))
+		}
+		fmt.Println()
+	}
+}
+
+func init() {
+	addResourceCmd.Flags().String("fields", "", "Fields of the resource as name:type pairs, e.g. name:string,price:float,stock:int")
+	addResourceCmd.Flags().String("dir", ".", "Directory of the project to add the resource to")
+	addResourceCmd.Flags().String("path", "", "Path the routes are mounted at (default /<plural name>)")
+	addResourceCmd.Flags().Bool("force", false, "Overwrite resource files that already exist")
+	addResourceCmd.Flags().Bool("dry-run", false, "Report what would be generated without writing anything")
+	addResourceCmd.Flags().String("output", "text", "Output format: text or json")
+	addResourceCmd.MarkFlagRequired("fields")
+
+	addCmd.AddCommand(addResourceCmd)
+	RootCmd.AddCommand(addCmd)
+}
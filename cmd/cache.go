@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local template cache",
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := templateCache.Entries()
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("Template cache is empty:", templateCache.Dir)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SOURCE\tKEY\tSIZE\tETAG\tFETCHED")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", e.Source, e.Key, e.Size, e.ETag, e.FetchedAt.Local().Format(time.DateTime))
+		}
+		return w.Flush()
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove stale cache entries and unreferenced objects",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		olderThan, _ := cmd.Flags().GetDuration("older-than")
+		if all {
+			olderThan = 0
+		} else if olderThan <= 0 {
+			return errors.New("--older-than must be positive; use --all to empty the cache")
+		}
+
+		entries, objects, err := templateCache.Prune(olderThan)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d cache entries and %d objects from %s\n", entries, objects, templateCache.Dir)
+		return nil
+	},
+}
+
+var cacheWarmCmd = &cobra.Command{
+	Use:   "warm [prefix...]",
+	Short: "Download templates into the cache for offline use",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cachedSource == nil {
+			return errors.New("the cache is only used for S3 template sources")
+		}
+		if len(args) == 0 {
+			args = []string{"templates/"}
+		}
+
+		for _, prefix := range args {
+			keys, err := cachedSource.Warm(cmd.Context(), prefix)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Cached %d templates under %s/%s\n", len(keys), cachedSource, prefix)
+		}
+		return nil
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().Duration("older-than", 30*24*time.Hour, "Remove entries not revalidated within this duration")
+	cachePruneCmd.Flags().Bool("all", false, "Remove every cache entry")
+
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheWarmCmd)
+	RootCmd.AddCommand(cacheCmd)
+}
@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 
-	"github.com/TheRSTech/Backendforger-backend/cmd/generator"
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -14,23 +21,23 @@ var RootCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Use 'backendforger --help' to see available commands")
 	},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setupTemplateSource(cmd)
+	},
 }
 
 var createGoAppCmd = &cobra.Command{
 	Use:   "create-go-app [name]",
 	Short: "Create a new backend project in Go",
 	Args:  cobra.ExactArgs(1), // Expect exactly one argument (the app name)
-	Run: func(cmd *cobra.Command, args []string) {
-		appName := args[0]
-		framework, _ := cmd.Flags().GetString("framework")
-		database, _ := cmd.Flags().GetString("database")
-		orm, _ := cmd.Flags().GetString("orm")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := specFromFlags(cmd, "go", args[0])
 
-		fmt.Printf("Creating golang app '%s' with framework: %s, database: %s, orm: %s\n",
-			appName, framework, database, orm)
+		banner := fmt.Sprintf("Creating golang app '%s' with framework: %s, database: %s, orm: %s",
+			spec.Name, spec.Framework, spec.Database, spec.ORM)
 
-		// Call your generateGoProject function with appName, framework, database, orm
-		generator.GenerateGoProject(appName, framework, database, orm)
+		cmd.SilenceUsage = true
+		return runCreate(cmd, newGenerator(cmd), spec, banner)
 	},
 }
 
@@ -38,17 +45,14 @@ var createPythonAppCmd = &cobra.Command{
 	Use:   "create-python-app [name]",
 	Short: "Create a new backend project in Python",
 	Args:  cobra.ExactArgs(1), // Expect exactly one argument (the app name)
-	Run: func(cmd *cobra.Command, args []string) {
-		appName := args[0]
-		framework, _ := cmd.Flags().GetString("framework")
-		database, _ := cmd.Flags().GetString("database")
-		orm, _ := cmd.Flags().GetString("orm")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := specFromFlags(cmd, "python", args[0])
 
-		fmt.Printf("Creating python app '%s' with framework: %s, database: %s, orm: %s\n",
-			appName, framework, database, orm)
+		banner := fmt.Sprintf("Creating python app '%s' with framework: %s, database: %s, orm: %s",
+			spec.Name, spec.Framework, spec.Database, spec.ORM)
 
-		// Call your generatePythonProject function with appName, framework, database, orm
-		generator.GeneratePythonProject(appName, framework, database, orm)
+		cmd.SilenceUsage = true
+		return runCreate(cmd, newGenerator(cmd), spec, banner)
 	},
 }
 
@@ -56,43 +60,244 @@ var createNodeAppCmd = &cobra.Command{
 	Use:   "create-node-app [name]",
 	Short: "Create a new Node.js project",
 	Args:  cobra.ExactArgs(1), // Expect exactly one argument (the app name)
-	Run: func(cmd *cobra.Command, args []string) {
-		appName := args[0]
-		ts, _ := cmd.Flags().GetBool("typescript")
-		framework, _ := cmd.Flags().GetString("framework")
-		database, _ := cmd.Flags().GetString("database")
-		orm, _ := cmd.Flags().GetString("orm")
-
-		if ts {
-			fmt.Printf("Creating Node.js app '%s' with TypeScript, framework: %s, database: %s, orm: %s\n",
-				appName, framework, database, orm)
-		} else {
-			fmt.Printf("Creating Node.js app '%s' with JavaScript, framework: %s, database: %s, orm: %s\n",
-				appName, framework, database, orm)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := specFromFlags(cmd, "node", args[0])
+		spec.TypeScript, _ = cmd.Flags().GetBool("typescript")
+
+		lang := "JavaScript"
+		if spec.TypeScript {
+			lang = "TypeScript"
 		}
+		banner := fmt.Sprintf("Creating Node.js app '%s' with %s, framework: %s, database: %s, orm: %s",
+			spec.Name, lang, spec.Framework, spec.Database, spec.ORM)
 
-		generator.GenerateNodeProject(appName, framework, database, orm, ts)
+		cmd.SilenceUsage = true
+		return runCreate(cmd, newGenerator(cmd), spec, banner)
 	},
 }
 
+// newGenerator returns the generator configured by the generate flags of a
+// create command. The wizard previews its plan with the same generator, so
+// the preview matches what is generated.
+func newGenerator(cmd *cobra.Command) *forge.Generator {
+	g := &forge.Generator{Source: templateSource, Runner: commandRunner(cmd), Stdout: os.Stdout}
+	g.Dir, _ = cmd.Flags().GetString("output-dir")
+	g.KeepOnFailure, _ = cmd.Flags().GetBool("keep-on-failure")
+	g.Diffs, _ = cmd.Flags().GetBool("diff")
+	if force, _ := cmd.Flags().GetBool("force"); force {
+		g.Existing = forge.OverwriteExisting
+	} else if merge, _ := cmd.Flags().GetBool("merge"); merge {
+		g.Existing = forge.MergeExisting
+	}
+	g.CommandTimeout, _ = cmd.Flags().GetDuration("command-timeout")
+	g.SkipCommands, _ = cmd.Flags().GetBool("skip-install")
+	return g
+}
+
+// runCreate generates the project with g, or only prints its plan with
+// --dry-run. The banner is only printed for text output.
+func runCreate(cmd *cobra.Command, g *forge.Generator, spec forge.Spec, banner string) error {
+	output, _ := cmd.Flags().GetString("output")
+	if output != "text" && output != "json" {
+		return fmt.Errorf("invalid --output %q: use text or json", output)
+	}
+	if output == "text" {
+		fmt.Println(banner)
+	}
+	ctx := cmd.Context()
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		plan, err := g.Plan(ctx, spec)
+		if err != nil {
+			return withCreateHint(err)
+		}
+		if output == "json" {
+			return writeJSON(plan)
+		}
+		plan.WriteTree(os.Stdout)
+		return nil
+	}
+
+	if path, _ := cmd.Flags().GetString("events"); path != "" {
+		w := io.Writer(os.Stderr)
+		if path != "-" {
+			f, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		enc := json.NewEncoder(w)
+		g.Events = func(e forge.Event) { enc.Encode(e) }
+	}
+	if output == "json" {
+		g.Stdout = io.Discard
+	}
+
+	var result *forge.Result
+	var err error
+	if archive, _ := cmd.Flags().GetString("archive"); archive != "" {
+		setup, _ := cmd.Flags().GetBool("archive-setup")
+		result, err = createArchive(ctx, g, spec, archive, setup)
+	} else {
+		result, err = g.Generate(ctx, spec)
+	}
+	err = withCreateHint(err)
+	if output == "json" {
+		if result == nil {
+			// Generation never started, e.g. the archive could not be
+			// created; still report the failure as a result.
+			result = forge.NewResult(spec)
+			result.Errors = []string{err.Error()}
+		}
+		if jsonErr := writeJSON(result); jsonErr != nil && err == nil {
+			err = jsonErr
+		}
+	}
+	return err
+}
+
+// withCreateHint adds to err how to get past it, for the errors that have a
+// flag or command for that.
+func withCreateHint(err error) error {
+	var missing *forge.MissingToolsError
+	var exists *forge.TargetExistsError
+	switch {
+	case errors.As(err, &missing):
+		err = fmt.Errorf("%w\nRun 'backendforger doctor' for details, or pass --skip-install to generate without running setup commands", err)
+	case errors.As(err, &exists):
+		err = fmt.Errorf("%w\nPass --merge to only add missing files, or --force to overwrite generated files", err)
+	}
+	return err
+}
+
+// createArchive generates the project into the archive at path. The
+// archive is written to a temporary file that only replaces path once
+// generation has succeeded.
+func createArchive(ctx context.Context, g *forge.Generator, spec forge.Spec, path string, setup bool) (*forge.Result, error) {
+	format, err := forge.ParseArchiveFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".backendforger-*")
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := g.Archive(ctx, spec, f, format, setup)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return result, err
+	}
+
+	result.Archive, _ = filepath.Abs(path)
+	fmt.Fprintf(g.Stdout, "Project archived to %s\n", color.BlueString(path))
+	return result, nil
+}
+
+// commandRunner runs external commands, logging them to stderr with --verbose.
+func commandRunner(cmd *cobra.Command) forge.CommandRunner {
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		return forge.LoggingRunner{Runner: forge.ExecRunner{}, Log: os.Stderr}
+	}
+	return forge.ExecRunner{}
+}
+
+// writeJSON prints v as indented JSON on stdout.
+func writeJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// specFromFlags reads the project flags shared by the create commands.
+func specFromFlags(cmd *cobra.Command, language, name string) forge.Spec {
+	spec := forge.Spec{Language: language, Name: name}
+	spec.Framework, _ = cmd.Flags().GetString("framework")
+	spec.Database, _ = cmd.Flags().GetString("database")
+	spec.ORM, _ = cmd.Flags().GetString("orm")
+	spec.Features, _ = cmd.Flags().GetStringSlice("feature")
+	spec.Module = moduleFlag(cmd)
+	return spec
+}
+
+// addProjectFlags defines the project flags of a language's create command.
+// The choices in the help texts come from the embedded templates; see
+// 'backendforger list' for those of the configured source.
+func addProjectFlags(cmd *cobra.Command, language string) {
+	frameworks, databases, orms := embeddedChoices(language)
+	cmd.Flags().StringP("framework", "f", "", "Framework: "+frameworks)
+	cmd.Flags().StringP("database", "d", "", "Database (optional): "+databases)
+	cmd.Flags().StringP("orm", "o", "", "ORM (optional): "+orms)
+	cmd.Flags().StringSlice("feature", nil, "Optional features to include (see 'backendforger list features')")
+	if language == "go" {
+		cmd.Flags().String("module", "", "Go module path, e.g. github.com/acme/myapp (default the project name)")
+	} else {
+		cmd.Flags().String("package-name", "", "Package name used inside the project (default the project name)")
+	}
+}
+
+// moduleFlag returns --module or --package-name, whichever the command has
+// and was given.
+func moduleFlag(cmd *cobra.Command) string {
+	for _, name := range []string{"module", "package-name"} {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return f.Value.String()
+		}
+	}
+	return ""
+}
+
+// addGenerateFlags defines the flags controlling how and where a project is
+// generated, shared by every create command.
+func addGenerateFlags(cmd *cobra.Command) {
+	cmd.Flags().String("output-dir", ".", "Directory to create the project directory in")
+	cmd.Flags().Bool("force", false, "Generate into an existing project directory, overwriting files the project generates")
+	cmd.Flags().Bool("merge", false, "Generate into an existing project directory, only writing missing files and reporting conflicts")
+	cmd.Flags().Bool("diff", false, "With --merge or --force, show a diff for every existing file that differs")
+	cmd.MarkFlagsMutuallyExclusive("force", "merge")
+	cmd.Flags().Bool("keep-on-failure", false, "Keep the partially generated project if generation fails")
+	cmd.Flags().Bool("dry-run", false, "Print the files and commands that would be produced without writing anything")
+	cmd.Flags().String("output", "text", "Output format: text or json")
+	cmd.Flags().String("events", "", "Write NDJSON progress events to this file ('-' for stderr)")
+	cmd.Flags().String("archive", "", "Write the project to this .zip or .tar.gz file instead of a directory")
+	cmd.Flags().Duration("command-timeout", 0, "Stop any setup command that runs longer than this, e.g. 5m (default no limit)")
+	cmd.Flags().Bool("skip-install", false, "Do not run setup commands such as go mod init, npm install or pip install; also skips the toolchain check")
+	cmd.Flags().Bool("archive-setup", false, "With --archive, run setup commands (go mod init, npm install, ...) in a temporary directory and include their output")
+}
+
 func init() {
+	RootCmd.Version = forge.Version
+	RootCmd.PersistentFlags().String("template-source", "", "Where to read templates from: s3, s3://bucket, a local directory, or embed (manifests only, unless built after 'go generate ./templates') (default s3)")
+	RootCmd.PersistentFlags().String("s3-bucket", "", "S3 bucket holding the templates (default backendforger)")
+	RootCmd.PersistentFlags().String("s3-region", "", "AWS region of the template bucket (default from AWS config, else ap-south-1)")
+	RootCmd.PersistentFlags().BoolP("verbose", "v", false, "Log every external command with its directory, duration and outcome")
+	RootCmd.PersistentFlags().Bool("offline", false, "Only use templates from the local cache; never contact S3")
+
 	// Define flags for createGoAppCmd
-	createGoAppCmd.Flags().StringP("framework", "f", "", "Framework (e.g. gin, echo, flask, express, fastapi, fiber, mux)")
-	createGoAppCmd.Flags().StringP("database", "d", "", "Database (e.g. sqlite, postgres, mysql, mongodb)")
-	createGoAppCmd.Flags().StringP("orm", "o", "", "ORM (optional)")
+	addProjectFlags(createGoAppCmd, "go")
+	addGenerateFlags(createGoAppCmd)
 	createGoAppCmd.MarkFlagRequired("framework")
 
 	// Define flags for createPythonAppCmd
-	createPythonAppCmd.Flags().StringP("framework", "f", "", "Framework (e.g. flask, fast api)")
-	createPythonAppCmd.Flags().StringP("database", "d", "", "Database (e.g. sqlite, postgres, mysql")
-	createPythonAppCmd.Flags().StringP("orm", "o", "", "ORM (optional)")
+	addProjectFlags(createPythonAppCmd, "python")
+	addGenerateFlags(createPythonAppCmd)
 	createPythonAppCmd.MarkFlagRequired("framework")
 
 	// Define flags for createNodeAppCmd
 	createNodeAppCmd.Flags().BoolP("typescript", "t", false, "Use TypeScript for Node.js")
-	createNodeAppCmd.Flags().StringP("framework", "f", "", "Framework (e.g. express)")
-	createNodeAppCmd.Flags().StringP("database", "d", "", "Database (e.g. mongodb)")
-	createNodeAppCmd.Flags().StringP("orm", "o", "", "ORM (optional)")
+	addProjectFlags(createNodeAppCmd, "node")
+	addGenerateFlags(createNodeAppCmd)
 	createNodeAppCmd.MarkFlagRequired("framework")
 
 	// Add createGoAppCmd and createNodeAppCmd to rootCmd
@@ -101,7 +306,8 @@ func init() {
 	RootCmd.AddCommand(createPythonAppCmd)
 	RootCmd.AddCommand(createNodeAppCmd)
 
-	// Set a custom error handler
+	// Errors are printed once by main
+	RootCmd.SilenceErrors = true
 	RootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	RootCmd.SuggestionsMinimumDistance = 1
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+	"github.com/spf13/cobra"
+)
+
+// config holds settings read from the user's config file. Command line
+// flags take precedence over anything set here.
+type config struct {
+	TemplateSource string `json:"template_source"`
+	S3Bucket       string `json:"s3_bucket"`
+	S3Region       string `json:"s3_region"`
+	CacheDir       string `json:"cache_dir"`
+}
+
+// configPath returns $BACKENDFORGER_CONFIG if set, otherwise
+// <user config dir>/backendforger/config.json.
+func configPath() (string, error) {
+	if path := os.Getenv("BACKENDFORGER_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backendforger", "config.json"), nil
+}
+
+// loadConfig reads the config file. A missing file is not an error.
+func loadConfig() (config, error) {
+	var cfg config
+
+	path, err := configPath()
+	if err != nil {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// templateSource, templateCache and cachedSource are set up by
+// setupTemplateSource. cachedSource is nil unless templates come from S3.
+var (
+	templateSource forge.TemplateSource
+	templateCache  *forge.TemplateCache
+	cachedSource   *forge.CachedSource
+
+	// s3Bucket, s3Region and offline are the resolved settings, used to
+	// open other sources the same way (see openTemplateSource).
+	s3Bucket, s3Region string
+	offline            bool
+)
+
+// setupTemplateSource resolves the template source from the flags and the
+// config file. Remote sources are always read through the local cache.
+func setupTemplateSource(cmd *cobra.Command) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	source := stringFlagOr(cmd, "template-source", cfg.TemplateSource)
+	s3Bucket = stringFlagOr(cmd, "s3-bucket", cfg.S3Bucket)
+	s3Region = stringFlagOr(cmd, "s3-region", cfg.S3Region)
+	offline, _ = cmd.Flags().GetBool("offline")
+
+	cacheDir := cfg.CacheDir
+	if cacheDir == "" {
+		if cacheDir, err = forge.DefaultCacheDir(); err != nil {
+			// No home directory, e.g. under env -i: cache in the temp dir.
+			cacheDir = filepath.Join(os.TempDir(), "backendforger")
+		}
+	}
+	templateCache = forge.NewTemplateCache(cacheDir)
+
+	src, err := openTemplateSource(source)
+	if err != nil {
+		return err
+	}
+	cachedSource, _ = src.(*forge.CachedSource)
+	templateSource = src
+	return nil
+}
+
+// openTemplateSource parses a --template-source value. Remote sources are
+// read through the local cache.
+func openTemplateSource(value string) (forge.TemplateSource, error) {
+	src, err := forge.ParseTemplateSource(value, s3Bucket, s3Region)
+	if err != nil {
+		return nil, err
+	}
+	if remote, ok := src.(forge.RemoteSource); ok {
+		return &forge.CachedSource{Remote: remote, Cache: templateCache, Offline: offline}, nil
+	}
+	return src, nil
+}
+
+// stringFlagOr returns the flag's value if it was set on the command line, otherwise fallback.
+func stringFlagOr(cmd *cobra.Command, name, fallback string) string {
+	if cmd.Flags().Changed(name) {
+		value, _ := cmd.Flags().GetString(name)
+		return value
+	}
+	return fallback
+}
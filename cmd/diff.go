@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [dir]",
+	Short: "Show how a generated project has drifted from its templates",
+	Long: `Re-render a project in memory from the spec in its ` + forge.LockFileName + ` and compare
+it with the working tree. Modified and deleted files are shown as unified diffs
+from the template to your copy; files no template produced are listed as added,
+except under .git, node_modules and virtual environments.
+
+The templates are those of the version recorded in the lockfile, so the template
+source must still provide it; pass --source with the original templates, or
+--latest to compare against the current ones instead.`,
+	Example: "  backendforger diff\n  backendforger diff ./api --summary --output json\n  backendforger diff --exit-code",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q: use text or json", output)
+		}
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		cmd.SilenceUsage = true
+
+		var opts forge.DiffOptions
+		opts.Latest, _ = cmd.Flags().GetBool("latest")
+		opts.Summary, _ = cmd.Flags().GetBool("summary")
+		g := &forge.Generator{Source: templateSource}
+		if source, _ := cmd.Flags().GetString("source"); source != "" {
+			src, err := openTemplateSource(source)
+			if err != nil {
+				return err
+			}
+			g.Source = src
+		}
+
+		drift, err := g.Diff(cmd.Context(), dir, opts)
+		var invalid *forge.ValidationError
+		if errors.As(err, &invalid) && !opts.Latest {
+			return fmt.Errorf("%w\nPass --source with the templates the project was generated from, or --latest to compare with the current ones", err)
+		}
+		if err != nil {
+			return err
+		}
+		if output == "json" {
+			if err := writeJSON(drift); err != nil {
+				return err
+			}
+		} else {
+			printDrift(drift)
+		}
+		if exitCode, _ := cmd.Flags().GetBool("exit-code"); exitCode && drift.Summary.Drifted() {
+			return fmt.Errorf("%s has drifted from its templates", dir)
+		}
+		return nil
+	},
+}
+
+func printDrift(drift *forge.Drift) {
+	colors := map[string]func(string, ...any) string{
+		forge.DriftModified: color.YellowString,
+		forge.DriftDeleted:  color.RedString,
+		forge.DriftAdded:    color.GreenString,
+	}
+	for _, f := range drift.Files {
+		if f.Status != forge.DriftUnchanged {
+			fmt.Printf("\t%s %s\n", colors[f.Status]("%-8s", f.Status), f.Path)
+		}
+	}
+	s := drift.Summary
+	if !s.Drifted() {
+		fmt.Printf("%s matches templates %s.\n", drift.ProjectPath, drift.TemplateVersion)
+		return
+	}
+	fmt.Printf("%s against templates %s: %d modified, %d deleted, %d added, %d unchanged\n",
+		drift.ProjectPath, drift.TemplateVersion, s.Modified, s.Deleted, s.Added, s.Unchanged)
+	for _, f := range drift.Files {
+		if f.Diff != "" {
+			fmt.Println()
+			fmt.Print(f.Diff)
+		}
+	}
+}
+
+func init() {
+	diffCmd.Flags().String("source", "", "Template source holding the templates the project was generated from")
+	diffCmd.Flags().Bool("latest", false, "Compare against the current templates instead of the version in the lockfile")
+	diffCmd.Flags().Bool("summary", false, "List the files without their diffs")
+	diffCmd.Flags().Bool("exit-code", false, "Exit with status 1 if the project has drifted")
+	diffCmd.Flags().String("output", "text", "Output format: text or json")
+
+	RootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:     "doctor",
+	Short:   "Check that the tools used by the generated projects are installed",
+	Example: "  backendforger doctor\n  backendforger doctor --lang python --output json",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q: use text or json", output)
+		}
+		lang, _ := cmd.Flags().GetString("lang")
+		tools := forge.ToolsFor(lang)
+		if len(tools) == 0 {
+			return fmt.Errorf("unknown language %q", lang)
+		}
+		cmd.SilenceUsage = true
+
+		statuses := forge.CheckTools(cmd.Context(), commandRunner(cmd), tools)
+		failed := 0
+		for _, s := range statuses {
+			if !s.OK() {
+				failed++
+			}
+		}
+
+		if output == "json" {
+			if err := writeJSON(statuses); err != nil {
+				return err
+			}
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TOOL\tSTATUS\tVERSION\tDETAILS")
+			for _, s := range statuses {
+				switch {
+				case s.OK():
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Name, color.GreenString("ok"), s.Version, s.Path)
+				case s.Found:
+					fmt.Fprintf(w, "%s\t%s\t-\t%s; %s\n", s.Name, color.RedString("broken"), s.Error, s.Hint)
+				default:
+					fmt.Fprintf(w, "%s\t%s\t-\t%s\n", s.Name, color.RedString("missing"), s.Hint)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d tools are missing or broken", failed, len(statuses))
+		}
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().String("lang", "", "Only check the tools of this language (go, python or node)")
+	doctorCmd.Flags().String("output", "text", "Output format: text or json")
+
+	RootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:       "list frameworks|databases|orms|features",
+	Short:     "List the supported frameworks, databases, ORMs and features",
+	Example:   "  backendforger list frameworks\n  backendforger list databases --lang go\n  backendforger list orms --lang node --output json",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"frameworks", "databases", "orms", "features"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q: use text or json", output)
+		}
+		lang, _ := cmd.Flags().GetString("lang")
+		framework, _ := cmd.Flags().GetString("framework")
+		cmd.SilenceUsage = true
+
+		reg, err := forge.LoadRegistry(cmd.Context(), templateSource)
+		if err != nil {
+			return err
+		}
+		if lang != "" && reg.Catalog().Language(lang) == nil {
+			return fmt.Errorf("unknown language %q", lang)
+		}
+		manifests, err := reg.Manifests(cmd.Context(), lang)
+		if err != nil {
+			return err
+		}
+		if framework != "" {
+			manifests = slices.DeleteFunc(manifests, func(m *forge.Manifest) bool { return m.Framework != framework })
+		}
+
+		var rows listRows
+		switch args[0] {
+		case "frameworks":
+			rows = listFrameworks(manifests)
+		case "databases":
+			rows = listDatabases(manifests)
+		case "orms":
+			rows = listORMs(manifests)
+		case "features":
+			rows = listFeatures(manifests)
+		}
+
+		if output == "json" {
+			return writeJSON(rows.records)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(rows.header, "\t"))
+		for _, r := range rows.cells {
+			fmt.Fprintln(w, strings.Join(r, "\t"))
+		}
+		return w.Flush()
+	},
+}
+
+// listRows is a listing both as table cells and as JSON records.
+type listRows struct {
+	header  []string
+	cells   [][]string
+	records []map[string]any
+}
+
+func (r *listRows) add(record map[string]any, cells ...string) {
+	r.cells = append(r.cells, cells)
+	r.records = append(r.records, record)
+}
+
+func listFrameworks(manifests []*forge.Manifest) listRows {
+	rows := listRows{header: []string{"LANGUAGE", "FRAMEWORK", "VERSION", "DESCRIPTION"}, records: []map[string]any{}}
+	for _, m := range manifests {
+		rows.add(map[string]any{
+			"language":    m.Language,
+			"framework":   m.Framework,
+			"version":     m.Version,
+			"description": m.Description,
+		}, m.Language, m.Framework, m.Version, m.Description)
+	}
+	return rows
+}
+
+func listDatabases(manifests []*forge.Manifest) listRows {
+	rows := listRows{header: []string{"LANGUAGE", "FRAMEWORK", "DATABASE", "ORMS"}, records: []map[string]any{}}
+	for _, m := range manifests {
+		for _, db := range m.Databases() {
+			orms := []string{}
+			for _, c := range m.Combinations {
+				if slices.Contains(c.Databases, db) {
+					orms = append(orms, ormName(c.ORM))
+				}
+			}
+			rows.add(map[string]any{
+				"language":  m.Language,
+				"framework": m.Framework,
+				"database":  db,
+				"orms":      orms,
+			}, m.Language, m.Framework, db, strings.Join(orms, ", "))
+		}
+	}
+	return rows
+}
+
+func listORMs(manifests []*forge.Manifest) listRows {
+	rows := listRows{header: []string{"LANGUAGE", "FRAMEWORK", "ORM", "DATABASES", "DEFAULT", "TYPESCRIPT"}, records: []map[string]any{}}
+	for _, m := range manifests {
+		for _, c := range m.Combinations {
+			ts := "any"
+			if c.TypeScript != nil {
+				ts = map[bool]string{true: "required", false: "unsupported"}[*c.TypeScript]
+			}
+			databases := c.Databases
+			if databases == nil {
+				databases = []string{}
+			}
+			rows.add(map[string]any{
+				"language":         m.Language,
+				"framework":        m.Framework,
+				"orm":              c.ORM,
+				"databases":        databases,
+				"default_database": c.DefaultDatabase,
+				"typescript":       ts,
+			}, m.Language, m.Framework, ormName(c.ORM), orDash(strings.Join(databases, ", ")), orDash(c.DefaultDatabase), ts)
+		}
+	}
+	return rows
+}
+
+func listFeatures(manifests []*forge.Manifest) listRows {
+	rows := listRows{header: []string{"LANGUAGE", "FRAMEWORK", "FEATURE", "DESCRIPTION"}, records: []map[string]any{}}
+	for _, m := range manifests {
+		for _, f := range m.Features {
+			rows.add(map[string]any{
+				"language":    m.Language,
+				"framework":   m.Framework,
+				"feature":     f.Name,
+				"description": f.Description,
+			}, m.Language, m.Framework, f.Name, f.Description)
+		}
+	}
+	return rows
+}
+
+// ormName shows the combination without --orm as "(none)".
+func ormName(orm string) string {
+	if orm == "" {
+		return "(none)"
+	}
+	return orm
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// embeddedChoices lists the frameworks, databases and ORMs the embedded
+// templates offer for a language, so flag help texts follow the registry.
+func embeddedChoices(language string) (frameworks, databases, orms string) {
+	reg, err := forge.LoadRegistry(context.Background(), forge.NewEmbedSource())
+	if err != nil {
+		return "", "", ""
+	}
+	manifests, err := reg.Manifests(context.Background(), language)
+	if err != nil {
+		return "", "", ""
+	}
+	var fws, dbs, ormList []string
+	for _, m := range manifests {
+		fws = append(fws, m.Framework)
+		for _, db := range m.Databases() {
+			if !slices.Contains(dbs, db) {
+				dbs = append(dbs, db)
+			}
+		}
+		for _, orm := range m.ORMs() {
+			if !slices.Contains(ormList, orm) {
+				ormList = append(ormList, orm)
+			}
+		}
+	}
+	return strings.Join(fws, ", "), strings.Join(dbs, ", "), strings.Join(ormList, ", ")
+}
+
+func init() {
+	listCmd.Flags().String("lang", "", "Only list entries for this language (go, python or node)")
+	listCmd.Flags().String("framework", "", "Only list entries for this framework")
+	listCmd.Flags().String("output", "text", "Output format: text or json")
+
+	RootCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/server"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the generator as an HTTP API",
+	Long: `Serve the generator as an HTTP API.
+
+Endpoints:
+  GET  /v1/frameworks?language=            supported frameworks with their databases, ORMs and features
+  GET  /v1/databases?language=&framework=  supported databases
+  GET  /v1/orms?language=&framework=       supported ORMs
+  POST /v1/validate                        check a project spec
+  POST /v1/generate?format=zip|tar.gz      generate a project and return it as an archive
+
+Specs are JSON objects such as {"language": "go", "name": "myapp", "framework": "gin"}.
+Projects are generated in memory; setup commands such as go mod init are not run.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("addr")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		maxConcurrent, _ := cmd.Flags().GetInt("max-concurrent")
+		allowOrigin, _ := cmd.Flags().GetString("cors-origin")
+
+		srv := &http.Server{
+			Addr: addr,
+			Handler: server.New(templateSource, server.Options{
+				Timeout:       timeout,
+				MaxConcurrent: maxConcurrent,
+				AllowOrigin:   allowOrigin,
+			}).Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       timeout,
+			WriteTimeout:      timeout + 10*time.Second,
+		}
+
+		// The command's context is cancelled on Ctrl-C or SIGTERM.
+		ctx := cmd.Context()
+		shutdown := make(chan error, 1)
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			shutdown <- srv.Shutdown(shutdownCtx)
+		}()
+
+		cmd.SilenceUsage = true
+		fmt.Printf("Serving on %s (templates from %s)\n", addr, templateSource)
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		// ListenAndServe returns as soon as Shutdown is called; wait for the
+		// generations in flight to finish.
+		if err := <-shutdown; err != nil {
+			return fmt.Errorf("shutting down: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("addr", ":8080", "Address to listen on")
+	serveCmd.Flags().Duration("timeout", 30*time.Second, "Maximum time to handle one request")
+	serveCmd.Flags().Int("max-concurrent", runtime.NumCPU(), "Maximum number of projects generated at once")
+	serveCmd.Flags().String("cors-origin", "", "Value of Access-Control-Allow-Origin, e.g. the frontend's URL")
+
+	RootCmd.AddCommand(serveCmd)
+}
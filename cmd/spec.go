@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a new backend project from a spec file or flags",
+	Long: `Create a new backend project from a backendforger.yaml spec file or flags.
+
+Flags given on the command line override the values read from the spec file,
+and the name argument overrides the spec's name. Run in a terminal without a
+spec file, language or framework, it asks for the project interactively.`,
+	Example: `  backendforger create
+  backendforger create -f backendforger.yaml
+  backendforger create myapp --language go --framework gin -o gorm -d postgres`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		var spec forge.Spec
+		if path, _ := cmd.Flags().GetString("file"); path != "" {
+			var err error
+			if spec, err = forge.ReadSpecFile(path); err != nil {
+				return err
+			}
+		}
+		if len(args) == 1 {
+			spec.Name = args[0]
+		}
+		applySpecFlags(cmd, &spec)
+
+		// Without a spec file or project flags, ask for the project in a
+		// terminal; elsewhere validation reports what is missing.
+		g := newGenerator(cmd)
+		output, _ := cmd.Flags().GetString("output")
+		if !cmd.Flags().Changed("file") && spec.Language == "" && spec.Framework == "" && output == "text" && stdinIsTerminal() {
+			var err error
+			spec, err = newWizard(g, os.Stdin, os.Stdout).run(cmd.Context(), spec)
+			if errors.Is(err, errAborted) {
+				fmt.Println("Aborted.")
+				return nil
+			}
+			if err != nil {
+				return withCreateHint(err)
+			}
+		}
+
+		banner := fmt.Sprintf("Creating %s app '%s' with framework: %s, database: %s, orm: %s",
+			spec.Language, spec.Name, spec.Framework, spec.Database, spec.ORM)
+		return runCreate(cmd, g, spec, banner)
+	},
+}
+
+var specCmd = &cobra.Command{
+	Use:   "spec",
+	Short: "Manage backendforger.yaml project specs",
+}
+
+var specInitCmd = &cobra.Command{
+	Use:   "init [name]",
+	Short: "Write the spec file equivalent to a set of flags",
+	Long: `Write the spec file equivalent to a set of flags, pinned to the current
+template version, so the project can be recreated with 'backendforger create -f'.`,
+	Example: `  backendforger spec init myapp --language go --framework gin -o gorm -d postgres`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var spec forge.Spec
+		if len(args) == 1 {
+			spec.Name = args[0]
+		}
+		applySpecFlags(cmd, &spec)
+		cmd.SilenceUsage = true
+
+		g := &forge.Generator{Source: templateSource}
+		if err := g.Validate(cmd.Context(), spec); err != nil {
+			return err
+		}
+		manifest, err := forge.LoadManifest(cmd.Context(), templateSource, spec.Language, spec.Framework)
+		if err != nil {
+			return err
+		}
+		spec.TemplateVersion = manifest.Version
+
+		data, err := forge.MarshalSpecFile(spec)
+		if err != nil {
+			return err
+		}
+		path, _ := cmd.Flags().GetString("file")
+		if path == "-" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("%s already exists; use --force to overwrite it", path)
+			}
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\nCreate the project with:\n\tbackendforger create -f %s\n", color.BlueString(path), path)
+		return nil
+	},
+}
+
+// addSpecFlags defines the flags describing a project, for commands that
+// also accept a spec file and therefore cannot use -f for --framework.
+func addSpecFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("language", "l", "", "Language: go, python or node")
+	cmd.Flags().String("framework", "", "Framework (e.g. gin, flask, express)")
+	cmd.Flags().StringP("database", "d", "", "Database (e.g. sqlite, postgres, mysql, mongodb)")
+	cmd.Flags().StringP("orm", "o", "", "ORM (optional)")
+	cmd.Flags().BoolP("typescript", "t", false, "Use TypeScript (node only)")
+	cmd.Flags().StringSlice("feature", nil, "Optional features to include (e.g. docker)")
+	cmd.Flags().String("module", "", "Go module path (default the project name)")
+	cmd.Flags().String("package-name", "", "npm or Python package name (default the project name)")
+}
+
+// applySpecFlags overrides spec with the flags set on the command line.
+func applySpecFlags(cmd *cobra.Command, spec *forge.Spec) {
+	flags := cmd.Flags()
+	if flags.Changed("language") {
+		spec.Language, _ = flags.GetString("language")
+	}
+	if flags.Changed("framework") {
+		spec.Framework, _ = flags.GetString("framework")
+	}
+	if flags.Changed("database") {
+		spec.Database, _ = flags.GetString("database")
+	}
+	if flags.Changed("orm") {
+		spec.ORM, _ = flags.GetString("orm")
+	}
+	if flags.Changed("typescript") {
+		spec.TypeScript, _ = flags.GetBool("typescript")
+	}
+	if flags.Changed("feature") {
+		spec.Features, _ = flags.GetStringSlice("feature")
+	}
+	if module := moduleFlag(cmd); module != "" {
+		spec.Module = module
+	}
+}
+
+func init() {
+	createCmd.Flags().StringP("file", "f", "", "Read the project spec from this file (e.g. "+forge.SpecFileName+")")
+	addSpecFlags(createCmd)
+	addGenerateFlags(createCmd)
+
+	specInitCmd.Flags().StringP("file", "f", forge.SpecFileName, "File to write the spec to ('-' for stdout)")
+	specInitCmd.Flags().Bool("force", false, "Overwrite an existing spec file")
+	addSpecFlags(specInitCmd)
+
+	specCmd.AddCommand(specInitCmd)
+	RootCmd.AddCommand(createCmd)
+	RootCmd.AddCommand(specCmd)
+}
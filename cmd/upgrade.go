@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [dir]",
+	Short: "Apply newer templates to a generated project",
+	Long: `Re-render a project from its ` + forge.LockFileName + ` with the current templates and
+merge the changes into it. Files you did not modify are replaced; files you
+modified are merged three-way against the original templates, and conflicts
+are marked in the file. The original templates are rendered from the template
+source if it still has the version in the lockfile, else from the template
+cache, else from --base-source. Setup commands are not run.`,
+	Example: "  backendforger upgrade\n  backendforger upgrade ./api --base-source ./templates-v1 --dry-run --diff",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			return fmt.Errorf("invalid --output %q: use text or json", output)
+		}
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		cmd.SilenceUsage = true
+
+		opts := forge.UpgradeOptions{Cache: templateCache}
+		opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		opts.Diffs, _ = cmd.Flags().GetBool("diff")
+		if base, _ := cmd.Flags().GetString("base-source"); base != "" {
+			src, err := openTemplateSource(base)
+			if err != nil {
+				return err
+			}
+			opts.Base = src
+		}
+
+		g := &forge.Generator{Source: templateSource}
+		result, err := g.Upgrade(cmd.Context(), dir, opts)
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			if err := writeJSON(result); err != nil {
+				return err
+			}
+		} else {
+			printUpgrade(result, opts)
+		}
+		if result.Conflicts > 0 && !opts.DryRun {
+			return fmt.Errorf("conflict markers were written to %d file(s); resolve them and commit", result.Conflicts)
+		}
+		return nil
+	},
+}
+
+func printUpgrade(result *forge.UpgradeResult, opts forge.UpgradeOptions) {
+	counts := map[string]int{}
+	noBase := false
+	colors := map[string]func(string, ...any) string{
+		forge.UpgradeUpdated:  color.GreenString,
+		forge.UpgradeMerged:   color.GreenString,
+		forge.UpgradeAdded:    color.GreenString,
+		forge.UpgradeConflict: color.RedString,
+		forge.UpgradeDeleted:  color.YellowString,
+		forge.UpgradeRemoved:  color.YellowString,
+	}
+	for _, f := range result.Files {
+		counts[f.Action]++
+		noBase = noBase || (f.NoBase && f.Action == forge.UpgradeConflict)
+		if f.Action == forge.UpgradeUnchanged {
+			continue
+		}
+		fmt.Printf("\t%s %s\n", colors[f.Action]("%-9s", f.Action), f.Path)
+	}
+	changed := len(result.Files) - counts[forge.UpgradeUnchanged]
+	if changed == 0 && result.From == result.To {
+		fmt.Printf("Already up to date with templates %s.\n", result.To)
+		return
+	}
+
+	verb := "Upgraded"
+	if opts.DryRun {
+		verb = "Would upgrade"
+	}
+	fmt.Printf("%s %s from templates %s to %s: %d updated, %d merged, %d conflicting, %d added, %d unchanged\n",
+		verb, result.ProjectPath, result.From, result.To,
+		counts[forge.UpgradeUpdated], counts[forge.UpgradeMerged], counts[forge.UpgradeConflict],
+		counts[forge.UpgradeAdded], counts[forge.UpgradeUnchanged])
+	if counts[forge.UpgradeDeleted] > 0 {
+		fmt.Println("Files you deleted were not recreated.")
+	}
+	if counts[forge.UpgradeRemoved] > 0 {
+		fmt.Println("Files the templates no longer generate were kept; delete them if you do not need them.")
+	}
+	if noBase {
+		fmt.Println("The original templates were not found; pass --base-source with them to merge your changes instead of conflicting on whole files.")
+	}
+	for _, f := range result.Files {
+		if f.Diff != "" {
+			fmt.Println()
+			fmt.Print(f.Diff)
+		}
+	}
+}
+
+func init() {
+	upgradeCmd.Flags().String("base-source", "", "Template source holding the templates the project was generated from, if neither the template source nor the cache still has them")
+	upgradeCmd.Flags().Bool("dry-run", false, "Report what would change without writing anything")
+	upgradeCmd.Flags().Bool("diff", false, "Show the diff of every file the upgrade changes")
+	upgradeCmd.Flags().String("output", "text", "Output format: text or json")
+
+	RootCmd.AddCommand(upgradeCmd)
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// errAborted is returned when the user declines the wizard's preview.
+var errAborted = errors.New("aborted")
+
+// stdinIsTerminal reports whether the wizard can prompt the user.
+func stdinIsTerminal() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// wizard asks for the parts of a Spec that are still missing. Only options
+// the registry accepts together with the earlier answers are offered.
+type wizard struct {
+	g   *forge.Generator
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newWizard(g *forge.Generator, in io.Reader, out io.Writer) *wizard {
+	return &wizard{g: g, in: bufio.NewReader(in), out: out}
+}
+
+// option is one numbered choice.
+type option struct {
+	value string
+	label string
+}
+
+// run completes spec interactively, previews the project tree and asks for
+// confirmation.
+func (w *wizard) run(ctx context.Context, spec forge.Spec) (forge.Spec, error) {
+	reg, err := forge.LoadRegistry(ctx, w.g.Source)
+	if err != nil {
+		return spec, err
+	}
+
+	if spec.Language == "" {
+		var options []option
+		for _, l := range reg.Catalog().Languages {
+			options = append(options, option{l.Name, forge.LanguageName(l.Name)})
+		}
+		if spec.Language, err = w.choose(ctx, "Language", options, false); err != nil {
+			return spec, err
+		}
+	}
+
+	if spec.Name, err = w.askName(ctx, spec); err != nil {
+		return spec, err
+	}
+
+	if spec.Framework == "" {
+		manifests, err := reg.Manifests(ctx, spec.Language)
+		if err != nil {
+			return spec, err
+		}
+		var options []option
+		for _, m := range manifests {
+			options = append(options, option{m.Framework, m.Description})
+		}
+		if spec.Framework, err = w.choose(ctx, "Framework", options, false); err != nil {
+			return spec, err
+		}
+	}
+
+	manifest, err := reg.Manifest(ctx, spec.Language, spec.Framework)
+	if err != nil {
+		return spec, err
+	}
+
+	if spec.Language == "node" && !spec.TypeScript {
+		if spec.TypeScript, err = w.confirm(ctx, "Use TypeScript?", false); err != nil {
+			return spec, err
+		}
+	}
+
+	if spec.Database == "" {
+		dbs, err := reg.DatabaseOptions(ctx, spec)
+		if err != nil {
+			return spec, err
+		}
+		if spec.Database, err = w.chooseValue(ctx, "Database", dbs); err != nil {
+			return spec, err
+		}
+	}
+
+	if spec.ORM == "" {
+		orms, err := reg.ORMOptions(ctx, spec)
+		if err != nil {
+			return spec, err
+		}
+		if spec.ORM, err = w.chooseValue(ctx, "ORM", orms); err != nil {
+			return spec, err
+		}
+	}
+
+	if len(spec.Features) == 0 {
+		for _, f := range manifest.Features {
+			on, err := w.confirm(ctx, fmt.Sprintf("Add %s (%s)?", f.Name, f.Description), false)
+			if err != nil {
+				return spec, err
+			}
+			if on {
+				spec.Features = append(spec.Features, f.Name)
+			}
+		}
+	}
+
+	plan, err := w.g.Plan(ctx, spec)
+	if err != nil {
+		return spec, err
+	}
+	fmt.Fprintln(w.out)
+	plan.WriteTree(w.out)
+	fmt.Fprintln(w.out)
+
+	ok, err := w.confirm(ctx, "Generate this project?", true)
+	if err != nil {
+		return spec, err
+	}
+	if !ok {
+		return spec, errAborted
+	}
+	return spec, nil
+}
+
+// askName returns the project name of spec, prompting for it until it is
+// valid for the language so a bad name is fixed before the other questions.
+func (w *wizard) askName(ctx context.Context, spec forge.Spec) (string, error) {
+	for {
+		if spec.Name != "" {
+			err := forge.CheckName(spec)
+			if err == nil {
+				return spec.Name, nil
+			}
+			fmt.Fprintln(w.out, color.YellowString(err.Error()))
+		}
+		var err error
+		if spec.Name, err = w.ask(ctx, "Project name", ""); err != nil {
+			return "", err
+		}
+	}
+}
+
+// chooseValue offers values, where an empty string stands for "none". It
+// asks nothing when there is no real choice.
+func (w *wizard) chooseValue(ctx context.Context, title string, values []string) (string, error) {
+	optional := slices.Contains(values, "")
+	var options []option
+	for _, v := range values {
+		if v != "" {
+			options = append(options, option{v, ""})
+		}
+	}
+	switch {
+	case len(options) == 0:
+		return "", nil
+	case len(options) == 1 && !optional:
+		fmt.Fprintf(w.out, "%s: %s (the only option)\n", color.CyanString(title), options[0].value)
+		return options[0].value, nil
+	}
+	return w.choose(ctx, title, options, optional)
+}
+
+// ask prompts for a free-form answer, repeating until it is non-empty
+// unless there is a default.
+func (w *wizard) ask(ctx context.Context, prompt, def string) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(w.out, "%s [%s]: ", color.CyanString(prompt), def)
+		} else {
+			fmt.Fprintf(w.out, "%s: ", color.CyanString(prompt))
+		}
+		answer, err := w.readLine(ctx)
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+		if answer != "" {
+			return answer, nil
+		}
+	}
+}
+
+// choose prompts for one of options by number or value. With optional,
+// choice 0 selects none and is the default.
+func (w *wizard) choose(ctx context.Context, title string, options []option, optional bool) (string, error) {
+	fmt.Fprintln(w.out, color.CyanString(title))
+	if optional {
+		fmt.Fprintln(w.out, "  0) none")
+	}
+	for i, o := range options {
+		if o.label != "" {
+			fmt.Fprintf(w.out, "  %d) %s - %s\n", i+1, o.value, o.label)
+		} else {
+			fmt.Fprintf(w.out, "  %d) %s\n", i+1, o.value)
+		}
+	}
+
+	def := "1"
+	if optional {
+		def = "0"
+	}
+	for {
+		answer, err := w.ask(ctx, "Choose", def)
+		if err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(answer); err == nil {
+			if optional && n == 0 {
+				return "", nil
+			}
+			if n >= 1 && n <= len(options) {
+				return options[n-1].value, nil
+			}
+		}
+		for _, o := range options {
+			if strings.EqualFold(answer, o.value) {
+				return o.value, nil
+			}
+		}
+		fmt.Fprintln(w.out, color.YellowString("Please pick one of the listed options."))
+	}
+}
+
+// confirm asks a yes/no question.
+func (w *wizard) confirm(ctx context.Context, prompt string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		fmt.Fprintf(w.out, "%s [%s]: ", color.CyanString(prompt), hint)
+		answer, err := w.readLine(ctx)
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// readLine reads one line of input. A read from a terminal only returns once
+// Enter is pressed, so it runs in the background and readLine gives up as
+// soon as ctx is done, e.g. on Ctrl-C.
+func (w *wizard) readLine(ctx context.Context) (string, error) {
+	type read struct {
+		line string
+		err  error
+	}
+	done := make(chan read, 1)
+	go func() {
+		line, err := w.in.ReadString('\n')
+		done <- read{line, err}
+	}()
+	var line string
+	var err error
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case r := <-done:
+		line, err = r.line, r.err
+	}
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		if errors.Is(err, io.EOF) {
+			return "", errors.New("input closed before the wizard finished")
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+	"github.com/spf13/cobra"
+)
+
+func TestWizardAsksAgainForInvalidName(t *testing.T) {
+	tests := []struct {
+		spec  forge.Spec
+		input string
+		want  string
+		warn  string
+	}{
+		{forge.Spec{Language: "go"}, "my app\nmyapp\n", "myapp", "must not contain spaces"},
+		{forge.Spec{Language: "python", Name: "my-app"}, "my_app\n", "my_app", "not a valid Python package name"},
+		{forge.Spec{Language: "node", Name: "api"}, "", "api", ""},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		w := newWizard(&forge.Generator{}, strings.NewReader(tt.input), &out)
+		got, err := w.askName(context.Background(), tt.spec)
+		if err != nil {
+			t.Errorf("askName(%+v): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("askName(%+v) = %q, want %q", tt.spec, got, tt.want)
+		}
+		if tt.warn != "" && !strings.Contains(out.String(), tt.warn) {
+			t.Errorf("askName(%+v) printed %q, want a warning containing %q", tt.spec, out.String(), tt.warn)
+		}
+	}
+}
+
+func TestWizardPromptReturnsOnCancel(t *testing.T) {
+	in, _ := io.Pipe() // never written, like a terminal nobody types into
+	w := newWizard(&forge.Generator{}, in, io.Discard)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.ask(ctx, "Project name", "")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ask returned %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ask did not return after the context was cancelled")
+	}
+}
+
+func TestWizardPreviewsWithTheCreateFlags(t *testing.T) {
+	old := templateSource
+	templateSource = forge.NewEmbedSource()
+	t.Cleanup(func() { templateSource = old })
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "svc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "svc", "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	spec := forge.Spec{Language: "go", Name: "svc", Framework: "gin", Database: "sqlite", ORM: "gorm", Features: []string{"docker"}}
+
+	for _, merge := range []bool{false, true} {
+		cmd := &cobra.Command{}
+		addGenerateFlags(cmd)
+		cmd.Flags().Set("output-dir", dir)
+		if merge {
+			cmd.Flags().Set("merge", "true")
+		}
+		_, err := newWizard(newGenerator(cmd), strings.NewReader("\n"), io.Discard).run(context.Background(), spec)
+		var exists *forge.TargetExistsError
+		if merge && err != nil {
+			t.Errorf("with --merge: %v", err)
+		}
+		if !merge && !errors.As(err, &exists) {
+			t.Errorf("without --merge: err = %v, want a *TargetExistsError", err)
+		}
+	}
+}
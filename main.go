@@ -1,16 +1,32 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/TheRSTech/Backendforger-backend/cmd"
 )
 
 func main() {
+	// Ctrl-C or SIGTERM cancels the context, which stops template downloads
+	// and running commands and removes the partially generated project. A
+	// second Ctrl-C kills the process immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
-	if err := cmd.RootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	err := cmd.RootCmd.ExecuteContext(ctx)
+	if err != nil && ctx.Err() != nil {
+		fmt.Fprintln(os.Stderr, "Interrupted.")
+		os.Exit(130)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,248 @@
+package forge
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ArchiveFormat is the container format of a generated project archive.
+type ArchiveFormat string
+
+const (
+	ArchiveZip   ArchiveFormat = "zip"
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+)
+
+// ParseArchiveFormat accepts a format name or a file name ending in one
+// of .zip, .tar.gz or .tgz.
+func ParseArchiveFormat(name string) (ArchiveFormat, error) {
+	switch {
+	case name == "zip" || strings.HasSuffix(name, ".zip"):
+		return ArchiveZip, nil
+	case name == "tar.gz" || name == "tgz" || strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz"):
+		return ArchiveTarGz, nil
+	}
+	return "", fmt.Errorf("unsupported archive format %q: use zip or tar.gz", name)
+}
+
+// ContentType is the MIME type of the format.
+func (f ArchiveFormat) ContentType() string {
+	if f == ArchiveZip {
+		return "application/zip"
+	}
+	return "application/gzip"
+}
+
+// Archive generates the project described by spec and writes it to w as an
+// archive whose entries are below a directory named after the project.
+//
+// Without setup the project is built in memory and no commands are run.
+// With setup it is built in a temporary directory so that commands such as
+// go mod init or npm install run and their output is archived too;
+// after-commit commands are skipped since they would record the temporary
+// location.
+func (g *Generator) Archive(ctx context.Context, spec Spec, w io.Writer, format ArchiveFormat, setup bool) (*Result, error) {
+	ag := *g
+	if !setup {
+		mem := NewMemFS()
+		ag.FS = mem
+		result, err := ag.Generate(ctx, spec)
+		result.ProjectPath = spec.Name
+		if err != nil {
+			return result, err
+		}
+		return result, WriteArchive(w, format, mem, spec.Name)
+	}
+
+	tmp, err := os.MkdirTemp("", "backendforger-archive-*")
+	if err != nil {
+		result := NewResult(spec)
+		result.Errors = []string{err.Error()}
+		return result, err
+	}
+	defer os.RemoveAll(tmp)
+
+	ag.FS = nil
+	ag.Dir = tmp
+	ag.archiving = true
+	result, err := ag.Generate(ctx, spec)
+	result.ProjectPath = spec.Name
+	if err != nil {
+		return result, err
+	}
+	return result, WriteDirArchive(w, format, filepath.Join(tmp, spec.Name), spec.Name)
+}
+
+// WriteArchive writes every file and directory of fsys to w in the given
+// format, below the directory prefix. Permissions are preserved.
+func WriteArchive(w io.Writer, format ArchiveFormat, fsys fs.FS, prefix string) error {
+	return writeArchive(w, format, fsys, prefix, nil)
+}
+
+// WriteDirArchive is WriteArchive for a directory on disk. Unlike an
+// os.DirFS, it also archives symbolic links, such as those npm creates in
+// node_modules/.bin.
+func WriteDirArchive(w io.Writer, format ArchiveFormat, dir, prefix string) error {
+	return writeArchive(w, format, os.DirFS(dir), prefix, func(name string) (string, error) {
+		return os.Readlink(filepath.Join(dir, filepath.FromSlash(name)))
+	})
+}
+
+// writeArchive walks fsys into an archive. readlink resolves symbolic
+// links; without it they are left out.
+func writeArchive(w io.Writer, format ArchiveFormat, fsys fs.FS, prefix string, readlink func(string) (string, error)) error {
+	aw, err := newArchiveWriter(w, format)
+	if err != nil {
+		return err
+	}
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || name == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		dest := path.Join(prefix, name)
+		if d.IsDir() {
+			return aw.dir(dest, info)
+		}
+		if info.Mode()&fs.ModeSymlink != 0 && readlink != nil {
+			target, err := readlink(name)
+			if err != nil {
+				return err
+			}
+			return aw.symlink(dest, info, target)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		return aw.file(dest, info, data)
+	})
+	if err != nil {
+		aw.Close()
+		return err
+	}
+	return aw.Close()
+}
+
+// archiveWriter adds entries to a zip or tar.gz stream.
+type archiveWriter interface {
+	dir(name string, info fs.FileInfo) error
+	file(name string, info fs.FileInfo, data []byte) error
+	symlink(name string, info fs.FileInfo, target string) error
+	Close() error
+}
+
+func newArchiveWriter(w io.Writer, format ArchiveFormat) (archiveWriter, error) {
+	switch format {
+	case ArchiveZip:
+		return &zipWriter{zip.NewWriter(w)}, nil
+	case ArchiveTarGz:
+		gz := gzip.NewWriter(w)
+		return &tarWriter{gz: gz, tw: tar.NewWriter(gz)}, nil
+	}
+	return nil, fmt.Errorf("unsupported archive format %q", format)
+}
+
+type zipWriter struct{ zw *zip.Writer }
+
+func (z *zipWriter) dir(name string, info fs.FileInfo) error {
+	h, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	h.Name = name + "/"
+	_, err = z.zw.CreateHeader(h)
+	return err
+}
+
+func (z *zipWriter) file(name string, info fs.FileInfo, data []byte) error {
+	h, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	h.Name = name
+	h.Method = zip.Deflate
+	fw, err := z.zw.CreateHeader(h)
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write(data)
+	return err
+}
+
+// symlink stores the link target as the entry's content, as Info-ZIP does.
+func (z *zipWriter) symlink(name string, info fs.FileInfo, target string) error {
+	h, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	h.Name = name
+	fw, err := z.zw.CreateHeader(h)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(fw, target)
+	return err
+}
+
+func (z *zipWriter) Close() error { return z.zw.Close() }
+
+type tarWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (t *tarWriter) dir(name string, info fs.FileInfo) error {
+	h, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	h.Name = name + "/"
+	return t.tw.WriteHeader(h)
+}
+
+func (t *tarWriter) file(name string, info fs.FileInfo, data []byte) error {
+	h, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	h.Name = name
+	h.Size = int64(len(data))
+	if err := t.tw.WriteHeader(h); err != nil {
+		return err
+	}
+	_, err = t.tw.Write(data)
+	return err
+}
+
+func (t *tarWriter) symlink(name string, info fs.FileInfo, target string) error {
+	h, err := tar.FileInfoHeader(info, target)
+	if err != nil {
+		return err
+	}
+	h.Name = name
+	return t.tw.WriteHeader(h)
+}
+
+func (t *tarWriter) Close() error {
+	if err := t.tw.Close(); err != nil {
+		t.gz.Close()
+		return err
+	}
+	return t.gz.Close()
+}
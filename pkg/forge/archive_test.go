@@ -0,0 +1,161 @@
+package forge
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// archiveEntry is an entry read back from an archive. Content holds the
+// target of a symbolic link.
+type archiveEntry struct {
+	Mode    fs.FileMode
+	Content string
+}
+
+// readArchive returns the entries of a zip or tar.gz archive by name.
+func readArchive(t *testing.T, format ArchiveFormat, data []byte) map[string]archiveEntry {
+	t.Helper()
+	entries := map[string]archiveEntry{}
+	if format == ArchiveZip {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries[f.Name] = archiveEntry{f.Mode(), string(content)}
+		}
+		return entries
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Typeflag == tar.TypeSymlink {
+			content = []byte(h.Linkname)
+		}
+		entries[h.Name] = archiveEntry{h.FileInfo().Mode(), string(content)}
+	}
+	return entries
+}
+
+// checkContained fails for entries that would be extracted outside root.
+func checkContained(t *testing.T, entries map[string]archiveEntry, root string) {
+	t.Helper()
+	for name := range entries {
+		clean := path.Clean(name)
+		if path.IsAbs(name) || strings.Contains(name, `\`) || (clean != root && !strings.HasPrefix(clean, root+"/")) {
+			t.Errorf("entry %q escapes %s/", name, root)
+		}
+	}
+}
+
+func TestWriteDirArchive(t *testing.T) {
+	dir := t.TempDir()
+	for name, file := range map[string]struct {
+		data string
+		perm fs.FileMode
+	}{
+		"main.go":         {"package main\n", 0644},
+		"bin/run.sh":      {"#!/bin/sh\n", 0755},
+		"config/app.yaml": {"port: 8080\n", 0600},
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(file.data), file.perm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(p, file.perm); err != nil { // past the umask
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("run.sh", filepath.Join(dir, "bin", "start")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]archiveEntry{
+		"svc/bin/":            {fs.ModeDir | 0755, ""},
+		"svc/bin/run.sh":      {0755, "#!/bin/sh\n"},
+		"svc/bin/start":       {fs.ModeSymlink | 0777, "run.sh"},
+		"svc/config/":         {fs.ModeDir | 0755, ""},
+		"svc/config/app.yaml": {0600, "port: 8080\n"},
+		"svc/main.go":         {0644, "package main\n"},
+	}
+	for _, format := range []ArchiveFormat{ArchiveZip, ArchiveTarGz} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteDirArchive(&buf, format, dir, "svc"); err != nil {
+				t.Fatal(err)
+			}
+			got := readArchive(t, format, buf.Bytes())
+			checkContained(t, got, "svc")
+			for name, w := range want {
+				g, ok := got[name]
+				switch {
+				case !ok:
+					t.Errorf("%s is missing", name)
+				case g.Mode.Type() != w.Mode.Type() || (w.Mode.Type() != fs.ModeSymlink && g.Mode.Perm() != w.Mode.Perm()):
+					t.Errorf("%s: mode = %v, want %v", name, g.Mode, w.Mode)
+				case g.Content != w.Content:
+					t.Errorf("%s: content = %q, want %q", name, g.Content, w.Content)
+				}
+			}
+			if len(got) != len(want) {
+				t.Errorf("got %d entries, want %d: %v", len(got), len(want), got)
+			}
+		})
+	}
+}
+
+func TestArchiveGeneratesBelowTheProjectName(t *testing.T) {
+	for _, format := range []ArchiveFormat{ArchiveZip, ArchiveTarGz} {
+		var buf bytes.Buffer
+		g := &Generator{Source: stubSource{}}
+		if _, err := g.Archive(context.Background(), Spec{Language: "go", Name: "svc", Framework: "gin"}, &buf, format, false); err != nil {
+			t.Fatal(err)
+		}
+		got := readArchive(t, format, buf.Bytes())
+		checkContained(t, got, "svc")
+		if e, ok := got["svc/main.go"]; !ok || e.Content != "// templates/go/gin/main.txt\n" {
+			t.Errorf("%s: svc/main.go = %+v, %v", format, e, ok)
+		}
+		if _, ok := got["svc/"+LockFileName]; !ok {
+			t.Errorf("%s: the lockfile is missing", format)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package forge
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotCached is returned in offline mode when a template has never been fetched.
+var ErrNotCached = errors.New("template is not in the local cache")
+
+// TemplateCache is a content-addressed store for templates fetched from a
+// remote source. Contents live in objects/<sha256>, and one index entry per
+// source/key in index/<sha256 of source/key>.json records the ETag used to
+// revalidate it.
+type TemplateCache struct {
+	Dir string
+}
+
+// CacheEntry describes one cached template.
+type CacheEntry struct {
+	Source    string    `json:"source"`
+	Key       string    `json:"key"`
+	ETag      string    `json:"etag"`
+	SHA256    string    `json:"sha256"`
+	Size      int64     `json:"size"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// DefaultCacheDir returns $BACKENDFORGER_CACHE_DIR if set, otherwise
+// <user cache dir>/backendforger.
+func DefaultCacheDir() (string, error) {
+	if dir := os.Getenv("BACKENDFORGER_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backendforger"), nil
+}
+
+// NewTemplateCache returns a cache rooted at dir. Directories are created on first write.
+func NewTemplateCache(dir string) *TemplateCache {
+	return &TemplateCache{Dir: dir}
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *TemplateCache) indexPath(source, key string) string {
+	return filepath.Join(c.Dir, "index", hashHex([]byte(source+"/"+key))+".json")
+}
+
+func (c *TemplateCache) objectPath(hash string) string {
+	return filepath.Join(c.Dir, "objects", hash)
+}
+
+// Lookup returns the entry and contents cached for source/key, or ErrNotCached.
+func (c *TemplateCache) Lookup(source, key string) (*CacheEntry, []byte, error) {
+	raw, err := os.ReadFile(c.indexPath(source, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, ErrNotCached
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, nil, ErrNotCached
+	}
+	data, err := os.ReadFile(c.objectPath(entry.SHA256))
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && hashHex(data) != entry.SHA256) {
+		return nil, nil, ErrNotCached
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &entry, data, nil
+}
+
+// Object returns the cached contents whose HashContent is hash, whichever
+// source and key they were fetched for, or ErrNotCached.
+func (c *TemplateCache) Object(hash string) ([]byte, error) {
+	hex, ok := strings.CutPrefix(hash, "sha256:")
+	if !ok {
+		return nil, ErrNotCached
+	}
+	data, err := os.ReadFile(c.objectPath(hex))
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && hashHex(data) != hex) {
+		return nil, ErrNotCached
+	}
+	return data, err
+}
+
+// Store saves data for source/key and records its ETag.
+func (c *TemplateCache) Store(source, key, etag string, data []byte) (*CacheEntry, error) {
+	entry := &CacheEntry{
+		Source:    source,
+		Key:       key,
+		ETag:      etag,
+		SHA256:    hashHex(data),
+		Size:      int64(len(data)),
+		FetchedAt: time.Now().UTC(),
+	}
+	if err := writeFileAtomic(c.objectPath(entry.SHA256), data); err != nil {
+		return nil, err
+	}
+	raw, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(c.indexPath(source, key), raw); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Touch marks an entry as revalidated without rewriting its contents.
+func (c *TemplateCache) Touch(entry *CacheEntry) error {
+	entry.FetchedAt = time.Now().UTC()
+	raw, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.indexPath(entry.Source, entry.Key), raw)
+}
+
+// Entries lists every cached template, sorted by source and key.
+func (c *TemplateCache) Entries() ([]CacheEntry, error) {
+	files, err := os.ReadDir(filepath.Join(c.Dir, "index"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []CacheEntry
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(c.Dir, "index", f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var entry CacheEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Source != entries[j].Source {
+			return entries[i].Source < entries[j].Source
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	return entries, nil
+}
+
+// Prune removes index entries not revalidated within maxAge (all of them
+// when maxAge is zero) and then deletes objects no entry refers to. It
+// returns the number of entries and objects removed.
+func (c *TemplateCache) Prune(maxAge time.Duration) (entries, objects int, err error) {
+	all, err := c.Entries()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	live := map[string]bool{}
+	for _, entry := range all {
+		if maxAge == 0 || entry.FetchedAt.Before(cutoff) {
+			if err := os.Remove(c.indexPath(entry.Source, entry.Key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return entries, objects, err
+			}
+			entries++
+			continue
+		}
+		live[entry.SHA256] = true
+	}
+
+	files, err := os.ReadDir(filepath.Join(c.Dir, "objects"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, objects, nil
+	}
+	if err != nil {
+		return entries, objects, err
+	}
+	for _, f := range files {
+		if live[f.Name()] {
+			continue
+		}
+		if err := os.Remove(c.objectPath(f.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return entries, objects, err
+		}
+		objects++
+	}
+	return entries, objects, nil
+}
+
+// writeFileAtomic writes through a temporary file so concurrent readers
+// never observe a partially written object.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// RemoteSource is a TemplateSource that supports conditional requests and
+// listing, which is what CachedSource needs to revalidate and warm the cache.
+type RemoteSource interface {
+	TemplateSource
+	// FetchIfNoneMatch returns notModified when etag still matches the remote object.
+	FetchIfNoneMatch(ctx context.Context, key, etag string) (data []byte, newETag string, notModified bool, err error)
+	// Keys lists every template key under prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+}
+
+// CachedSource serves templates from a TemplateCache, revalidating them
+// against the remote source with their ETag. In offline mode the remote is
+// never contacted.
+type CachedSource struct {
+	Remote  RemoteSource
+	Cache   *TemplateCache
+	Offline bool
+}
+
+func (s *CachedSource) Fetch(ctx context.Context, key string) ([]byte, error) {
+	source := s.Remote.String()
+	entry, cached, err := s.Cache.Lookup(source, key)
+	if err != nil && !errors.Is(err, ErrNotCached) {
+		return nil, err
+	}
+
+	if s.Offline {
+		if entry == nil {
+			return nil, fmt.Errorf("%w: %s/%s (offline mode; run 'backendforger cache warm' while online)", ErrNotCached, source, key)
+		}
+		return cached, nil
+	}
+
+	etag := ""
+	if entry != nil {
+		etag = entry.ETag
+	}
+	data, newETag, notModified, err := s.Remote.FetchIfNoneMatch(ctx, key, etag)
+	if err != nil {
+		return nil, err
+	}
+	if notModified {
+		if err := s.Cache.Touch(entry); err != nil {
+			return nil, err
+		}
+		return cached, nil
+	}
+	if _, err := s.Cache.Store(source, key, newETag, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *CachedSource) String() string {
+	return s.Remote.String()
+}
+
+// Warm fetches every template under prefix into the cache and returns the keys fetched.
+func (s *CachedSource) Warm(ctx context.Context, prefix string) ([]string, error) {
+	if s.Offline {
+		return nil, errors.New("cannot warm the cache in offline mode")
+	}
+	keys, err := s.Remote.Keys(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if _, err := s.Fetch(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,97 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fakeRemote is a RemoteSource backed by a map. With offline set, every
+// request fails, so a test can prove the remote is never contacted.
+type fakeRemote struct {
+	objects map[string]string
+	offline bool
+}
+
+func (r *fakeRemote) Fetch(ctx context.Context, key string) ([]byte, error) {
+	data, _, _, err := r.FetchIfNoneMatch(ctx, key, "")
+	return data, err
+}
+
+func (r *fakeRemote) FetchIfNoneMatch(ctx context.Context, key, etag string) ([]byte, string, bool, error) {
+	if r.offline {
+		return nil, "", false, errors.New("remote contacted in offline mode")
+	}
+	data, ok := r.objects[key]
+	if !ok {
+		return nil, "", false, ErrTemplateNotFound
+	}
+	newETag := HashContent([]byte(data))
+	if newETag == etag {
+		return nil, etag, true, nil
+	}
+	return []byte(data), newETag, false, nil
+}
+
+func (r *fakeRemote) Keys(ctx context.Context, prefix string) ([]string, error) {
+	if r.offline {
+		return nil, errors.New("remote contacted in offline mode")
+	}
+	var keys []string
+	for key := range r.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (r *fakeRemote) String() string { return "s3://fake" }
+
+// ginBucket holds the project templates of the gin pack, without the index
+// or manifests, like the real bucket.
+var ginBucket = map[string]string{
+	"templates/go/gin/main.txt":      "package main\n\nimport \"yourapp/api\"\n\nfunc main() { api.Hello() }\n",
+	"templates/go/gin/api/hello.txt": "package api\n\nfunc Hello() {}\n",
+}
+
+func TestOfflineCreateAfterWarm(t *testing.T) {
+	ctx := context.Background()
+	cache := NewTemplateCache(t.TempDir())
+	remote := &fakeRemote{objects: ginBucket}
+	keys, err := (&CachedSource{Remote: remote, Cache: cache}).Warm(ctx, "templates/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != len(ginBucket) {
+		t.Fatalf("warmed %d templates, want %d", len(keys), len(ginBucket))
+	}
+
+	remote.offline = true
+	runner := &RecordingRunner{}
+	dir := t.TempDir()
+	g := &Generator{Source: &CachedSource{Remote: remote, Cache: cache, Offline: true}, Runner: runner, Dir: dir}
+	if _, err := g.Generate(ctx, Spec{Language: "go", Name: "svc", Framework: "gin"}); err != nil {
+		t.Fatal(err)
+	}
+	main, err := os.ReadFile(filepath.Join(dir, "svc", "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(main), `import "svc/api"`) {
+		t.Errorf("main.go was not rendered from the cache:\n%s", main)
+	}
+}
+
+func TestOfflineMissNamesCacheWarm(t *testing.T) {
+	src := &CachedSource{Remote: &fakeRemote{offline: true}, Cache: NewTemplateCache(t.TempDir()), Offline: true}
+	_, err := fetchTemplate(context.Background(), src, "templates/go/gin/main.txt")
+	if !errors.Is(err, ErrNotCached) || !strings.Contains(err.Error(), "cache warm") {
+		t.Errorf("err = %v, want ErrNotCached mentioning cache warm", err)
+	}
+}
@@ -0,0 +1,52 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// catalogKey is the template key of the index listing every framework.
+const catalogKey = "templates/index.json"
+
+// Catalog lists the languages and frameworks offered by a template source.
+// It is published as templates/index.json.
+type Catalog struct {
+	Languages []CatalogLanguage `json:"languages"`
+}
+
+// CatalogLanguage is a language and the frameworks available for it.
+type CatalogLanguage struct {
+	Name       string   `json:"name"`
+	Frameworks []string `json:"frameworks"`
+}
+
+// LoadCatalog reads the framework index from src.
+func LoadCatalog(ctx context.Context, src TemplateSource) (*Catalog, error) {
+	data, err := fetchMetadata(ctx, src, catalogKey)
+	if err != nil {
+		return nil, err
+	}
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid catalog %s: %w", catalogKey, err)
+	}
+	return &c, nil
+}
+
+// Language returns the named language, or nil if the catalog lacks it.
+func (c *Catalog) Language(name string) *CatalogLanguage {
+	for i := range c.Languages {
+		if c.Languages[i].Name == name {
+			return &c.Languages[i]
+		}
+	}
+	return nil
+}
+
+// HasFramework reports whether framework is offered for language.
+func (c *Catalog) HasFramework(language, framework string) bool {
+	l := c.Language(language)
+	return l != nil && slices.Contains(l.Frameworks, framework)
+}
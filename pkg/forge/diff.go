@@ -0,0 +1,198 @@
+package forge
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// edit is one line of an edit script turning a into b: ' ' keeps a[a],
+// '-' deletes a[a] and '+' inserts b[b]. a and b are always the positions
+// in both inputs the edit applies at.
+type edit struct {
+	op   byte
+	a, b int
+}
+
+// splitLines splits data into lines that keep their "\n".
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// maxEditDistance bounds the number of differing lines diffLines searches
+// for. The search keeps about d² ints for d differences, so inputs further
+// apart than this are diffed as one replacement instead.
+const maxEditDistance = 2000
+
+// diffLines returns the shortest edit script from a to b using Myers'
+// algorithm, or one deleting all of a and inserting all of b if they differ
+// in more than maxEditDistance lines.
+func diffLines(a, b []string) []edit {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds v[offset-d : offset+d+1] as it was before step d: the
+	// only diagonals step d reads, and so the only ones backtracking needs.
+	var trace [][]int
+
+search:
+	for d := 0; d <= n+m; d++ {
+		if d > maxEditDistance {
+			return replaceAll(n, m)
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	var script []edit
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[d+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			script = append(script, edit{' ', x, y})
+		}
+		if x == prevX {
+			script = append(script, edit{'+', x, y - 1})
+		} else {
+			script = append(script, edit{'-', x - 1, y})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		x, y = x-1, y-1
+		script = append(script, edit{' ', x, y})
+	}
+
+	for i, j := 0, len(script)-1; i < j; i, j = i+1, j-1 {
+		script[i], script[j] = script[j], script[i]
+	}
+	return script
+}
+
+// replaceAll returns the edit script deleting n lines and inserting m.
+func replaceAll(n, m int) []edit {
+	script := make([]edit, 0, n+m)
+	for i := 0; i < n; i++ {
+		script = append(script, edit{'-', i, 0})
+	}
+	for j := 0; j < m; j++ {
+		script = append(script, edit{'+', n, j})
+	}
+	return script
+}
+
+// isBinary reports whether data looks like a binary file.
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+}
+
+// unifiedDiff returns the unified diff from a to b, or "" if they are equal.
+func unifiedDiff(oldName, newName string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+	if isBinary(a) || isBinary(b) {
+		return fmt.Sprintf("Binary files %s and %s differ\n", oldName, newName)
+	}
+	al, bl := splitLines(a), splitLines(b)
+	script := diffLines(al, bl)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+	for i := 0; i < len(script); {
+		for i < len(script) && script[i].op == ' ' {
+			i++
+		}
+		if i == len(script) {
+			break
+		}
+		start, end := max(0, i-diffContext), i
+		for {
+			for end < len(script) && script[end].op != ' ' {
+				end++
+			}
+			next := end
+			for next < len(script) && script[next].op == ' ' {
+				next++
+			}
+			if next < len(script) && next-end <= 2*diffContext {
+				end = next
+				continue
+			}
+			end = min(len(script), end+diffContext)
+			break
+		}
+
+		hunk := script[start:end]
+		var aCount, bCount int
+		for _, e := range hunk {
+			if e.op != '+' {
+				aCount++
+			}
+			if e.op != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(hunk[0].a, aCount), hunkRange(hunk[0].b, bCount))
+		for _, e := range hunk {
+			line := ""
+			switch e.op {
+			case ' ', '-':
+				line = al[e.a]
+			case '+':
+				line = bl[e.b]
+			}
+			out.WriteByte(e.op)
+			out.WriteString(line)
+			if !strings.HasSuffix(line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// hunkRange formats the start and length of one side of a hunk.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
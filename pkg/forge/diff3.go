@@ -0,0 +1,86 @@
+package forge
+
+import (
+	"slices"
+	"strings"
+)
+
+// merge3 merges the changes from base to ours and from base to theirs,
+// like diff3 -m. Regions changed differently on both sides are written
+// between conflict markers labelled with oursLabel and theirsLabel, and
+// their number is returned.
+func merge3(base, ours, theirs []byte, oursLabel, theirsLabel string) ([]byte, int) {
+	b, o, t := splitLines(base), splitLines(ours), splitLines(theirs)
+	toOurs, toTheirs := matchLines(b, o), matchLines(b, t)
+
+	var out strings.Builder
+	conflicts := 0
+	write := func(lines []string) {
+		for _, l := range lines {
+			out.WriteString(l)
+		}
+	}
+	i, j, k := 0, 0, 0
+	for i < len(b) || j < len(o) || k < len(t) {
+		// Lines unchanged on both sides.
+		if i < len(b) && toOurs[i] == j && toTheirs[i] == k {
+			out.WriteString(b[i])
+			i, j, k = i+1, j+1, k+1
+			continue
+		}
+
+		// Find the next base line both sides kept, and merge the chunk up
+		// to it.
+		ni := i
+		for ni < len(b) && (toOurs[ni] < 0 || toTheirs[ni] < 0) {
+			ni++
+		}
+		nj, nk := len(o), len(t)
+		if ni < len(b) {
+			nj, nk = toOurs[ni], toTheirs[ni]
+		}
+		bc, oc, tc := b[i:ni], o[j:nj], t[k:nk]
+
+		switch {
+		case slices.Equal(oc, bc):
+			write(tc)
+		case slices.Equal(tc, bc) || slices.Equal(oc, tc):
+			write(oc)
+		default:
+			conflicts++
+			out.WriteString("<<<<<<< " + oursLabel + "\n")
+			writeChunk(&out, oc)
+			out.WriteString("=======\n")
+			writeChunk(&out, tc)
+			out.WriteString(">>>>>>> " + theirsLabel + "\n")
+		}
+		i, j, k = ni, nj, nk
+	}
+	return []byte(out.String()), conflicts
+}
+
+// matchLines maps every line of a to the line of b it is kept as, or -1
+// if it was deleted.
+func matchLines(a, b []string) []int {
+	m := make([]int, len(a))
+	for i := range m {
+		m[i] = -1
+	}
+	for _, e := range diffLines(a, b) {
+		if e.op == ' ' {
+			m[e.a] = e.b
+		}
+	}
+	return m
+}
+
+// writeChunk writes lines inside conflict markers, ending the last one
+// with a newline so the marker after it starts on its own line.
+func writeChunk(out *strings.Builder, lines []string) {
+	for _, l := range lines {
+		out.WriteString(l)
+	}
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		out.WriteString("\n")
+	}
+}
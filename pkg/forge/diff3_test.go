@@ -0,0 +1,40 @@
+package forge
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		conflicts          int
+	}{
+		{"unchanged", "a\nb\n", "a\nb\n", "a\nb\n", "a\nb\n", 0},
+		{"only ours", "a\nb\n", "a\nB\n", "a\nb\n", "a\nB\n", 0},
+		{"only theirs", "a\nb\n", "a\nb\n", "A\nb\n", "A\nb\n", 0},
+		{"same change", "a\nb\n", "a\nB\n", "a\nB\n", "a\nB\n", 0},
+		{"separate changes", "a\nb\nc\nd\n", "A\nb\nc\nd\n", "a\nb\nc\nD\n", "A\nb\nc\nD\n", 0},
+		{"ours appends, theirs prepends", "a\n", "a\nz\n", "0\na\n", "0\na\nz\n", 0},
+		{"ours deletes", "a\nb\nc\n", "a\nc\n", "a\nb\nc\nd\n", "a\nc\nd\n", 0},
+		{
+			"conflict", "a\nb\nc\n", "a\nours\nc\n", "a\ntheirs\nc\n",
+			"a\n<<<<<<< yours\nours\n=======\ntheirs\n>>>>>>> new\nc\n", 1,
+		},
+		{
+			"no base", "", "x\n", "y\n",
+			"<<<<<<< yours\nx\n=======\ny\n>>>>>>> new\n", 1,
+		},
+		{
+			"missing newline in conflict", "a\n", "b", "c\n",
+			"<<<<<<< yours\nb\n=======\nc\n>>>>>>> new\n", 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := merge3([]byte(tt.base), []byte(tt.ours), []byte(tt.theirs), "yours", "new")
+			if string(got) != tt.want || conflicts != tt.conflicts {
+				t.Errorf("merge3 = %q with %d conflicts, want %q with %d", got, conflicts, tt.want, tt.conflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package forge
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// applyScript rebuilds b from a and an edit script, checking the positions
+// the script claims.
+func applyScript(t *testing.T, a, b []string, script []edit) []string {
+	t.Helper()
+	var out []string
+	for _, e := range script {
+		switch e.op {
+		case ' ':
+			if a[e.a] != b[e.b] {
+				t.Fatalf("kept line a[%d] = %q differs from b[%d] = %q", e.a, a[e.a], e.b, b[e.b])
+			}
+			out = append(out, a[e.a])
+		case '+':
+			out = append(out, b[e.b])
+		}
+	}
+	return out
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		changes int
+	}{
+		{"equal", "a\nb\nc\n", "a\nb\nc\n", 0},
+		{"both empty", "", "", 0},
+		{"from empty", "", "a\nb\n", 2},
+		{"to empty", "a\nb\n", "", 2},
+		{"insert", "a\nc\n", "a\nb\nc\n", 1},
+		{"delete", "a\nb\nc\n", "a\nc\n", 1},
+		{"replace", "a\nb\nc\n", "a\nx\nc\n", 2},
+		{"move", "a\nb\nc\nd\n", "b\nc\nd\na\n", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := splitLines([]byte(tt.a)), splitLines([]byte(tt.b))
+			script := diffLines(a, b)
+			if got := strings.Join(applyScript(t, a, b, script), ""); got != tt.b {
+				t.Errorf("script produces %q, want %q", got, tt.b)
+			}
+			changes := 0
+			for _, e := range script {
+				if e.op != ' ' {
+					changes++
+				}
+			}
+			if changes != tt.changes {
+				t.Errorf("%d changes, want %d (the shortest script)", changes, tt.changes)
+			}
+		})
+	}
+}
+
+func TestDiffLinesLargeInputs(t *testing.T) {
+	lines := func(prefix string, n int) []string {
+		var out []string
+		for i := 0; i < n; i++ {
+			out = append(out, fmt.Sprintf("%s%d\n", prefix, i))
+		}
+		return out
+	}
+
+	// Few changes in a long file are still found exactly.
+	a := lines("line ", 50000)
+	b := append([]string{"new\n"}, a[:25000]...)
+	b = append(b, a[25001:]...)
+	script := diffLines(a, b)
+	if got, want := strings.Join(applyScript(t, a, b, script), ""), strings.Join(b, ""); got != want {
+		t.Fatal("the script does not produce b")
+	}
+	if changes := len(script) - (len(a) - 1); changes != 2 {
+		t.Errorf("%d changes, want 2", changes)
+	}
+
+	// Inputs with nothing in common beyond maxEditDistance are replaced.
+	a, b = lines("a", maxEditDistance), lines("b", maxEditDistance)
+	script = diffLines(a, b)
+	if got, want := strings.Join(applyScript(t, a, b, script), ""), strings.Join(b, ""); got != want {
+		t.Fatal("the script does not produce b")
+	}
+	if len(script) != len(a)+len(b) {
+		t.Errorf("script has %d edits, want %d", len(script), len(a)+len(b))
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	long := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"equal", "a\n", "a\n", ""},
+		{"binary", "a\x00", "b\x00", "Binary files old and new differ\n"},
+		{"from empty", "", "a\n", "--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n"},
+		{"to empty", "a\nb\n", "", "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{
+			"replace in context", "a\nb\nc\n", "a\nx\nc\n",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			"missing newline", "a\n", "a",
+			"--- old\n+++ new\n@@ -1 +1 @@\n-a\n+a\n\\ No newline at end of file\n",
+		},
+		{
+			"separate hunks", long, strings.Replace(strings.Replace(long, "1\n", "one\n", 1), "12\n", "twelve\n", 1),
+			"--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("old", "new", []byte(tt.a), []byte(tt.b)); got != tt.want {
+				t.Errorf("unifiedDiff:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
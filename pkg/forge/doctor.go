@@ -0,0 +1,152 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Tool is an external program some template packs need.
+type Tool struct {
+	Name string `json:"name"`
+	// VersionCommand prints the tool's version; its first argument is the
+	// binary looked up in PATH, or an alias of it such as python3.
+	VersionCommand []string `json:"-"`
+	// Hint tells the user how to install the tool.
+	Hint      string   `json:"hint"`
+	Languages []string `json:"languages"`
+}
+
+// Tools are the programs checked by CheckTools.
+var Tools = []Tool{
+	{Name: "go", VersionCommand: []string{"go", "version"}, Hint: "install Go from https://go.dev/dl/", Languages: []string{"go"}},
+	{Name: "node", VersionCommand: []string{"node", "--version"}, Hint: "install Node.js from https://nodejs.org/", Languages: []string{"node"}},
+	{Name: "npm", VersionCommand: []string{"npm", "--version"}, Hint: "npm ships with Node.js: https://nodejs.org/", Languages: []string{"node"}},
+	{Name: "python", VersionCommand: []string{"python", "--version"}, Hint: "install Python from https://www.python.org/downloads/ and make sure 'python' or 'python3' is on PATH", Languages: []string{"python"}},
+	{Name: "venv", VersionCommand: []string{"python", "-m", "venv", "--help"}, Hint: "install the venv module, e.g. 'apt install python3-venv'", Languages: []string{"python"}},
+	{Name: "pip", VersionCommand: []string{"python", "-m", "pip", "--version"}, Hint: "run 'python -m ensurepip --upgrade'", Languages: []string{"python"}},
+}
+
+// ToolStatus is the result of checking one tool.
+type ToolStatus struct {
+	Tool
+	Found   bool   `json:"found"`
+	Path    string `json:"path,omitempty"`
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// OK reports whether the tool was found and works.
+func (s ToolStatus) OK() bool {
+	return s.Found && s.Error == ""
+}
+
+// toolTimeout bounds each version command.
+const toolTimeout = 10 * time.Second
+
+// CheckTools looks up every tool and runs its version command, with the
+// binary generation would run: python3 where there is no python.
+func CheckTools(ctx context.Context, runner CommandRunner, tools []Tool) []ToolStatus {
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	var statuses []ToolStatus
+	for _, t := range tools {
+		st := ToolStatus{Tool: t}
+		args := slices.Clone(t.VersionCommand)
+		args[0] = resolveBinary(runner, args[0])
+		path, err := lookPath(runner, args[0])
+		if err != nil {
+			statuses = append(statuses, st)
+			continue
+		}
+		st.Found, st.Path = true, path
+
+		var out bytes.Buffer
+		err = runner.Run(ctx, Invocation{Args: args, Timeout: toolTimeout, Output: &out})
+		if err != nil {
+			st.Error = strings.TrimSpace(fmt.Sprintf("%v %s", err, firstLine(out.String())))
+		} else if t.Name == "venv" {
+			st.Version = "available"
+		} else {
+			st.Version = firstLine(out.String())
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
+
+// ToolsFor returns the tools used by a language, or every tool for "".
+func ToolsFor(language string) []Tool {
+	if language == "" {
+		return Tools
+	}
+	var tools []Tool
+	for _, t := range Tools {
+		if slices.Contains(t.Languages, language) {
+			tools = append(tools, t)
+		}
+	}
+	return tools
+}
+
+// MissingToolsError reports programs required by a project's commands that
+// are not on PATH.
+type MissingToolsError struct {
+	Tools []ToolStatus
+}
+
+func (e *MissingToolsError) Error() string {
+	var parts []string
+	for _, t := range e.Tools {
+		part := t.Name
+		if t.Hint != "" {
+			part += " (" + t.Hint + ")"
+		}
+		parts = append(parts, part)
+	}
+	return "missing required tools: " + strings.Join(parts, "; ")
+}
+
+// preflight checks that runner can find the binary of every required
+// command in p before anything is written.
+func preflight(p *plan, runner CommandRunner) error {
+	var missing []ToolStatus
+	for _, c := range slices.Concat(p.pre, p.post, p.afterCommit) {
+		if c.Optional || len(c.Run) == 0 {
+			continue
+		}
+		bin := c.Run[0]
+		if slices.ContainsFunc(missing, func(s ToolStatus) bool { return s.VersionCommand[0] == bin }) {
+			continue
+		}
+		if _, err := lookPath(runner, resolveBinary(runner, bin)); err != nil {
+			missing = append(missing, ToolStatus{Tool: toolFor(bin)})
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingToolsError{Tools: missing}
+	}
+	return nil
+}
+
+// toolFor returns the known tool for a binary, or a bare one.
+func toolFor(bin string) Tool {
+	for _, t := range Tools {
+		if t.VersionCommand[0] == bin && t.Name == bin {
+			return t
+		}
+	}
+	return Tool{Name: bin, VersionCommand: []string{bin}}
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
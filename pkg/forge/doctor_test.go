@@ -0,0 +1,91 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPreflight(t *testing.T) {
+	p := &plan{
+		pre:         []Command{{Run: []string{"go", "mod", "init", "svc"}}},
+		post:        []Command{{Run: []string{"npm", "install"}, Optional: true}},
+		afterCommit: []Command{{Run: []string{"python", "-m", "venv", "venv"}}},
+	}
+	tests := []struct {
+		missing []string
+		want    []string
+	}{
+		{nil, nil},
+		{[]string{"npm"}, nil},
+		{[]string{"python"}, nil},
+		{[]string{"go", "python", "python3"}, []string{"go", "python"}},
+	}
+	for _, tt := range tests {
+		err := preflight(p, &RecordingRunner{Missing: tt.missing})
+		var missing *MissingToolsError
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("missing %v: %v", tt.missing, err)
+			}
+			continue
+		}
+		if !errors.As(err, &missing) {
+			t.Fatalf("missing %v: err = %v, want a *MissingToolsError", tt.missing, err)
+		}
+		var names []string
+		for _, tool := range missing.Tools {
+			names = append(names, tool.Name)
+		}
+		if !slices.Equal(names, tt.want) {
+			t.Errorf("missing %v: reported %v, want %v", tt.missing, names, tt.want)
+		}
+		if !strings.Contains(err.Error(), "https://go.dev/dl/") {
+			t.Errorf("the error lacks the install hint for go: %v", err)
+		}
+	}
+}
+
+func TestCheckToolsFallsBackToPython3(t *testing.T) {
+	runner := &RecordingRunner{
+		Missing: []string{"python"},
+		Respond: func(inv Invocation) (string, error) { return "Python 3.12.1\n", nil },
+	}
+	statuses := CheckTools(context.Background(), runner, ToolsFor("python"))
+	for _, s := range statuses {
+		if !s.OK() || s.Path != "python3" {
+			t.Errorf("%s: %+v, want it found as python3", s.Name, s)
+		}
+	}
+	if statuses[0].Version != "Python 3.12.1" || statuses[1].Version != "available" {
+		t.Errorf("versions = %q, %q", statuses[0].Version, statuses[1].Version)
+	}
+	for _, c := range runner.Commands() {
+		if !strings.HasPrefix(c, "python3 ") {
+			t.Errorf("ran %q, want python3", c)
+		}
+	}
+
+	runner = &RecordingRunner{Missing: []string{"python", "python3"}}
+	for _, s := range CheckTools(context.Background(), runner, ToolsFor("python")) {
+		if s.Found {
+			t.Errorf("%s was found: %+v", s.Name, s)
+		}
+	}
+	if cmds := runner.Commands(); len(cmds) != 0 {
+		t.Errorf("ran %v for missing tools", cmds)
+	}
+}
+
+func TestCommandsRunPython3WithoutPython(t *testing.T) {
+	runner := &RecordingRunner{Missing: []string{"python"}}
+	g := &Generator{Source: stubSource{}, Runner: runner, Dir: t.TempDir()}
+	if _, err := g.Generate(context.Background(), Spec{Language: "python", Name: "api", Framework: "fastapi"}); err != nil {
+		t.Fatal(err)
+	}
+	if cmds := runner.Commands(); !slices.Contains(cmds, "python3 -m venv venv") {
+		t.Errorf("commands = %v, want python3 -m venv venv", cmds)
+	}
+}
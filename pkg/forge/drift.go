@@ -0,0 +1,180 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// Statuses of the files in a Drift.
+const (
+	DriftUnchanged = "unchanged"
+	DriftModified  = "modified"
+	DriftDeleted   = "deleted"
+	DriftAdded     = "added"
+)
+
+// driftIgnoredDirs are never reported as added: they hold dependencies,
+// virtual environments and VCS data rather than the project's sources.
+var driftIgnoredDirs = []string{".git", "node_modules", "venv", ".venv", "__pycache__"}
+
+// DiffOptions control Generator.Diff.
+type DiffOptions struct {
+	// Latest compares against the current templates instead of the
+	// version recorded in the lockfile.
+	Latest bool
+	// Summary leaves the diffs out of the result.
+	Summary bool
+}
+
+// Drift describes how a project differs from the templates it was
+// generated from.
+type Drift struct {
+	ProjectPath     string       `json:"project_path"`
+	TemplateVersion string       `json:"template_version"`
+	Summary         DriftSummary `json:"summary"`
+	Files           []DriftFile  `json:"files"`
+}
+
+// DriftSummary counts the files of a Drift by status.
+type DriftSummary struct {
+	Unchanged int `json:"unchanged"`
+	Modified  int `json:"modified"`
+	Deleted   int `json:"deleted"`
+	Added     int `json:"added"`
+}
+
+// Drifted reports whether anything differs from the templates.
+func (s DriftSummary) Drifted() bool {
+	return s.Modified+s.Deleted+s.Added > 0
+}
+
+// DriftFile is one file of a Drift. Diff goes from the rendered template
+// to the working tree and is empty for unchanged and added files.
+type DriftFile struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+// Diff renders the project in dir from the spec in its lockfile, in
+// memory, and compares the result with the files on disk. Files that no
+// template produced are reported as added, except in dependency and VCS
+// directories and those the lockfile records as written by the setup
+// commands or AddResource. A rendered file a command or AddResource changed
+// is unchanged as long as it is as they left it, and resource files are
+// compared with the hashes recorded when they were added.
+func (g *Generator) Diff(ctx context.Context, dir string, opts DiffOptions) (*Drift, error) {
+	lock, err := ReadLockFile(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s has no %s; only projects generated by this version of backendforger can be compared", dir, LockFileName)
+		}
+		return nil, err
+	}
+	spec := lock.Spec
+	if opts.Latest {
+		spec.TemplateVersion = ""
+	}
+	rendered, files, err := renderProject(ctx, g.source(), spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hashes of the files the commands or AddResource wrote or edited, as
+	// they left them; later entries win.
+	recorded := map[string]string{}
+	for _, f := range lock.Generated {
+		recorded[f.Path] = f.Hash
+	}
+	var resourceFiles []string
+	for _, r := range lock.Resources {
+		for _, f := range r.Edited {
+			recorded[f.Path] = f.Hash
+		}
+		for _, f := range r.Files {
+			if _, ok := recorded[f.Path]; !ok && rendered.File(f.Path) == nil {
+				resourceFiles = append(resourceFiles, f.Path)
+			}
+			recorded[f.Path] = f.Hash
+		}
+	}
+
+	drift := &Drift{ProjectPath: dir, TemplateVersion: rendered.Spec.TemplateVersion, Files: []DriftFile{}}
+	for _, f := range rendered.Files {
+		generated := files[f.Path]
+		current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(f.Path)))
+		file := DriftFile{Path: f.Path}
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			file.Status = DriftDeleted
+			drift.Summary.Deleted++
+			if !opts.Summary {
+				file.Diff = unifiedDiff("a/"+f.Path, "/dev/null", generated, nil)
+			}
+		case err != nil:
+			return nil, err
+		case HashContent(current) == f.Hash || HashContent(current) == recorded[f.Path]:
+			file.Status = DriftUnchanged
+			drift.Summary.Unchanged++
+		default:
+			file.Status = DriftModified
+			drift.Summary.Modified++
+			if !opts.Summary {
+				file.Diff = unifiedDiff("a/"+f.Path, "b/"+f.Path, generated, current)
+			}
+		}
+		drift.Files = append(drift.Files, file)
+	}
+	// Resource files are not rendered again, so they have no diff.
+	for _, p := range resourceFiles {
+		current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(p)))
+		file := DriftFile{Path: p}
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			file.Status = DriftDeleted
+			drift.Summary.Deleted++
+		case err != nil:
+			return nil, err
+		case HashContent(current) == recorded[p]:
+			file.Status = DriftUnchanged
+			drift.Summary.Unchanged++
+		default:
+			file.Status = DriftModified
+			drift.Summary.Modified++
+		}
+		drift.Files = append(drift.Files, file)
+	}
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && slices.Contains(driftIgnoredDirs, d.Name()) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if _, ok := recorded[rel]; !ok && rel != LockFileName && rendered.File(rel) == nil {
+			drift.Files = append(drift.Files, DriftFile{Path: rel, Status: DriftAdded})
+			drift.Summary.Added++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(drift.Files, func(i, j int) bool { return drift.Files[i].Path < drift.Files[j].Path })
+	return drift, nil
+}
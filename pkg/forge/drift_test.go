@@ -0,0 +1,54 @@
+package forge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// goModRunner answers go mod init and tidy by writing go.mod and go.sum,
+// like the real commands.
+func goModRunner() *RecordingRunner {
+	return &RecordingRunner{Respond: func(inv Invocation) (string, error) {
+		switch strings.Join(inv.Args, " ") {
+		case "go mod init svc":
+			return "", os.WriteFile(filepath.Join(inv.Dir, "go.mod"), []byte("module svc\n\ngo 1.22\n"), 0644)
+		case "go mod tidy":
+			return "", os.WriteFile(filepath.Join(inv.Dir, "go.sum"), []byte("example.com/x v1.0.0 h1:x=\n"), 0644)
+		}
+		return "", nil
+	}}
+}
+
+func TestFreshProjectHasNoDrift(t *testing.T) {
+	ctx := context.Background()
+	src := &CachedSource{Remote: &fakeRemote{objects: ginBucket}, Cache: NewTemplateCache(t.TempDir())}
+	g := &Generator{Source: src, Runner: goModRunner(), Dir: t.TempDir()}
+	if _, err := g.Generate(ctx, Spec{Language: "go", Name: "svc", Framework: "gin"}); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(g.Dir, "svc")
+	if _, err := os.Stat(filepath.Join(dir, "go.sum")); err != nil {
+		t.Fatalf("the commands did not run: %v", err)
+	}
+
+	drift, err := g.Diff(ctx, dir, DiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drift.Summary.Drifted() {
+		t.Errorf("fresh project drifted: %+v", drift.Files)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("todo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if drift, err = g.Diff(ctx, dir, DiffOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if drift.Summary.Added != 1 {
+		t.Errorf("added = %d, want 1 for notes.md: %+v", drift.Summary.Added, drift.Files)
+	}
+}
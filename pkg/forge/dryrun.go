@@ -0,0 +1,147 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Plan describes everything a generation would do, without doing it.
+type Plan struct {
+	ProjectPath string           `json:"project_path"`
+	Language    string           `json:"language"`
+	Framework   string           `json:"framework"`
+	Database    string           `json:"database,omitempty"`
+	ORM         string           `json:"orm,omitempty"`
+	TypeScript  bool             `json:"typescript,omitempty"`
+	Features    []string         `json:"features,omitempty"`
+	Directories []string         `json:"directories"`
+	Files       []PlannedFile    `json:"files"`
+	Commands    []PlannedCommand `json:"commands"`
+}
+
+// PlannedFile is a destination path and the template it is rendered from,
+// if any.
+type PlannedFile struct {
+	Path     string `json:"path"`
+	Template string `json:"template,omitempty"`
+}
+
+// PlannedCommand is an external command and when it runs: "pre" (before
+// templates are copied), "post" (after) or "after_commit" (once the project
+// is in its final location).
+type PlannedCommand struct {
+	Phase    string   `json:"phase"`
+	Args     []string `json:"args"`
+	Optional bool     `json:"optional,omitempty"`
+}
+
+// Plan resolves the manifest for spec into a Plan. It fetches the manifest
+// but writes nothing and runs no commands.
+func (g *Generator) Plan(ctx context.Context, spec Spec) (*Plan, error) {
+	p, target, err := g.prepare(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Plan{
+		ProjectPath: target,
+		Language:    spec.Language,
+		Framework:   spec.Framework,
+		Database:    spec.Database,
+		ORM:         spec.ORM,
+		TypeScript:  spec.TypeScript,
+		Features:    spec.Features,
+		Directories: append([]string{}, p.dirs...),
+		Files:       []PlannedFile{},
+		Commands:    []PlannedCommand{},
+	}
+	for _, f := range p.files {
+		out.Files = append(out.Files, PlannedFile{Path: f.Dest, Template: f.Template})
+	}
+	out.Files = append(out.Files, PlannedFile{Path: LockFileName})
+	for _, phase := range []struct {
+		name     string
+		commands []Command
+	}{{"pre", p.pre}, {"post", p.post}, {"after_commit", p.afterCommit}} {
+		for _, c := range phase.commands {
+			args, err := p.renderArgs(c)
+			if err != nil {
+				return nil, err
+			}
+			out.Commands = append(out.Commands, PlannedCommand{Phase: phase.name, Args: args, Optional: c.Optional})
+		}
+	}
+	return out, nil
+}
+
+// WriteTree prints the plan as a directory tree followed by the commands.
+func (p *Plan) WriteTree(w io.Writer) {
+	type node struct {
+		children map[string]*node
+		template string
+		dir      bool
+	}
+	root := &node{children: map[string]*node{}, dir: true}
+	add := func(p string, dir bool, template string) {
+		n := root
+		parts := strings.Split(path.Clean(p), "/")
+		for i, part := range parts {
+			child, ok := n.children[part]
+			if !ok {
+				child = &node{children: map[string]*node{}, dir: dir || i < len(parts)-1}
+				n.children[part] = child
+			}
+			n = child
+		}
+		n.template = template
+	}
+	for _, d := range p.Directories {
+		add(d, true, "")
+	}
+	for _, f := range p.Files {
+		add(f.Path, false, f.Template)
+	}
+
+	var walk func(n *node, prefix string)
+	walk = func(n *node, prefix string) {
+		names := make([]string, 0, len(n.children))
+		for name := range n.children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			child := n.children[name]
+			branch, next := "├── ", "│   "
+			if i == len(names)-1 {
+				branch, next = "└── ", "    "
+			}
+			switch {
+			case child.dir:
+				fmt.Fprintf(w, "%s%s%s/\n", prefix, branch, name)
+			case child.template != "":
+				fmt.Fprintf(w, "%s%s%s  ← %s\n", prefix, branch, name, child.template)
+			default:
+				fmt.Fprintf(w, "%s%s%s\n", prefix, branch, name)
+			}
+			walk(child, prefix+next)
+		}
+	}
+
+	fmt.Fprintf(w, "%s/\n", p.ProjectPath)
+	walk(root, "")
+
+	if len(p.Commands) > 0 {
+		fmt.Fprintln(w, "\nCommands:")
+		for _, c := range p.Commands {
+			optional := ""
+			if c.Optional {
+				optional = " (optional)"
+			}
+			fmt.Fprintf(w, "  %-12s %s%s\n", c.Phase, strings.Join(c.Args, " "), optional)
+		}
+	}
+}
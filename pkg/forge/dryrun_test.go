@@ -0,0 +1,35 @@
+package forge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlanHasNoSideEffects(t *testing.T) {
+	dir := t.TempDir()
+	runner := &RecordingRunner{}
+	g := &Generator{Source: stubSource{}, Runner: runner, Dir: filepath.Join(dir, "out")}
+	plan, err := g.Plan(context.Background(), Spec{Language: "go", Name: "svc", Framework: "gin", Features: []string{"docker"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "out", "svc"); plan.ProjectPath != want {
+		t.Errorf("project path = %s, want %s", plan.ProjectPath, want)
+	}
+	if len(plan.Files) == 0 || len(plan.Commands) == 0 {
+		t.Errorf("plan lists %d files and %d commands", len(plan.Files), len(plan.Commands))
+	}
+
+	if cmds := runner.Commands(); len(cmds) != 0 {
+		t.Errorf("commands ran: %v", cmds)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("the dry run wrote %v", entries)
+	}
+}
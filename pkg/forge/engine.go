@@ -0,0 +1,431 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// languageNames are used in the messages printed after generation.
+var languageNames = map[string]string{
+	"go":     "Go",
+	"python": "Python",
+	"node":   "Node.js",
+}
+
+// LanguageName returns the display name of a language, e.g. "Node.js" for
+// "node", or the language itself if it has none.
+func LanguageName(language string) string {
+	if name, ok := languageNames[language]; ok {
+		return name
+	}
+	return language
+}
+
+// maxConcurrentCopies limits how many templates are fetched at once.
+const maxConcurrentCopies = 8
+
+// generation holds the state of one Generate call.
+type generation struct {
+	*Generator
+	ctx    context.Context
+	spec   Spec
+	src    TemplateSource
+	plan   *plan
+	result *Result
+
+	mu     sync.Mutex
+	done   int
+	locked []LockedFile
+}
+
+// Generate creates the project described by spec by executing the manifest
+// of its framework. On disk, everything is written to a staging directory
+// next to the target, which is moved into place only once every template
+// and command has succeeded. The returned Result is never nil and
+// describes partial work on failure.
+func (g *Generator) Generate(ctx context.Context, spec Spec) (*Result, error) {
+	startTime := time.Now()
+	gen := &generation{
+		Generator: g,
+		ctx:       ctx,
+		spec:      spec,
+		src:       g.source(),
+		result:    NewResult(spec),
+	}
+
+	err := gen.run()
+	gen.result.DurationMS = time.Since(startTime).Milliseconds()
+	gen.result.Success = err == nil
+	if err != nil {
+		gen.result.Errors = append(gen.result.Errors, err.Error())
+	}
+
+	finished := Event{Type: EventGenerationFinished, Project: gen.result.ProjectPath, DurationMS: gen.result.DurationMS}
+	if err != nil {
+		finished.Error = err.Error()
+	}
+	gen.emit(finished)
+
+	if err == nil && g.FS == nil && !g.archiving {
+		gen.printSummary(time.Since(startTime))
+	}
+	return gen.result, err
+}
+
+func (g *generation) run() error {
+	p, target, err := g.prepare(g.ctx, g.spec)
+	if err != nil {
+		return err
+	}
+	g.plan = p
+	g.result.ProjectPath = target
+	g.emit(Event{Type: EventGenerationStarted, Project: target, Total: len(p.files)})
+
+	if g.FS != nil {
+		return g.build(g.FS, "")
+	}
+	if !g.SkipCommands {
+		if err := preflight(p, g.runner()); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	// The staging directory keeps the project's base name so tools that
+	// derive names from it (npm init) behave as they would in place.
+	stagingParent, err := os.MkdirTemp(filepath.Dir(target), ".backendforger-*")
+	if err != nil {
+		return fmt.Errorf("creating staging directory: %w", err)
+	}
+	staging := filepath.Join(stagingParent, filepath.Base(target))
+
+	err = g.build(DirFS(staging), staging)
+	if err == nil {
+		// Never move a project into place once generation was cancelled.
+		err = g.ctx.Err()
+	}
+	if err != nil {
+		if g.KeepOnFailure {
+			fmt.Fprintf(g.stdout(), "Generation failed; partial project kept in %s\n", color.YellowString(staging))
+		} else {
+			os.RemoveAll(stagingParent)
+		}
+		return err
+	}
+
+	if err := g.commit(staging, target); err != nil {
+		if !g.KeepOnFailure {
+			os.RemoveAll(stagingParent)
+		}
+		return fmt.Errorf("moving project into place: %w", err)
+	}
+	os.RemoveAll(stagingParent)
+
+	if g.archiving {
+		return g.runCommands("", "after_commit", p.afterCommit)
+	}
+	return g.runCommands(target, "after_commit", p.afterCommit)
+}
+
+func (g *generation) printSummary(elapsed time.Duration) {
+	w := g.stdout()
+	fmt.Fprintf(w, "%s project '%s' generated in %v %s\n", LanguageName(g.spec.Language), color.BlueString(g.spec.Name), elapsed.Round(time.Millisecond), "🚀🚀\n")
+	g.printMerge()
+	fmt.Fprintf(w, "Navigate to the project directory using:\n\tcd %s\n\n", color.BlueString(g.result.ProjectPath))
+	if g.SkipCommands {
+		var skipped []string
+		for _, c := range g.result.Commands {
+			if c.Skipped && !c.Optional {
+				skipped = append(skipped, strings.Join(c.Args, " "))
+			}
+		}
+		if len(skipped) > 0 {
+			fmt.Fprintln(w, "Setup commands were skipped; run them yourself:")
+			for _, c := range skipped {
+				fmt.Fprintf(w, "\t%s\n", color.MagentaString(c))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	for _, step := range g.plan.nextSteps {
+		fmt.Fprintln(w, step.Description)
+		for _, c := range step.Commands {
+			if rendered, err := g.plan.render(c); err == nil {
+				c = rendered
+			}
+			fmt.Fprintf(w, "\t%s\n", color.MagentaString(c))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, color.HiGreenString("Happy coding! 🎉🎉🎉"))
+}
+
+// printMerge lists what happened to each file written into an existing
+// project, followed by the diffs of the files that differ.
+func (g *generation) printMerge() {
+	if len(g.result.Merge) == 0 {
+		return
+	}
+	w := g.stdout()
+	counts := map[string]int{}
+	colors := map[string]func(string, ...any) string{
+		MergeCreated:     color.GreenString,
+		MergeSkipped:     fmt.Sprintf,
+		MergeOverwritten: color.YellowString,
+		MergeConflict:    color.RedString,
+	}
+	fmt.Fprintln(w, "Existing project updated:")
+	for _, e := range g.result.Merge {
+		counts[e.Action]++
+		if e.Reason != "" {
+			fmt.Fprintf(w, "\t%s %s (%s)\n", colors[e.Action]("%-11s", e.Action), e.Path, e.Reason)
+		} else {
+			fmt.Fprintf(w, "\t%s %s\n", colors[e.Action]("%-11s", e.Action), e.Path)
+		}
+	}
+	fmt.Fprintf(w, "%d created, %d skipped, %d overwritten, %d conflicting\n\n",
+		counts[MergeCreated], counts[MergeSkipped], counts[MergeOverwritten], counts[MergeConflict])
+	if counts[MergeConflict] > 0 {
+		fmt.Fprintln(w, "Conflicting files were left unchanged.")
+		fmt.Fprintln(w)
+	}
+	for _, e := range g.result.Merge {
+		if e.Diff != "" {
+			fmt.Fprintln(w, e.Diff)
+		}
+	}
+}
+
+// emit stamps and forwards an event to the Events callback.
+func (g *generation) emit(e Event) {
+	if g.Events == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	e.Time = time.Now().UTC()
+	g.Events(e)
+}
+
+// prepare resolves the plan for spec and, when writing to disk, checks
+// that the target can be written.
+func (g *Generator) prepare(ctx context.Context, spec Spec) (*plan, string, error) {
+	manifest, err := g.validate(ctx, spec)
+	if err != nil {
+		return nil, "", err
+	}
+	p, err := manifest.plan(spec)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dir := g.Dir
+	if dir == "" {
+		dir = "."
+	}
+	target, err := filepath.Abs(filepath.Join(dir, spec.Name))
+	if err != nil {
+		return nil, "", err
+	}
+	if g.FS == nil {
+		if err := checkTarget(target, g.Existing); err != nil {
+			return nil, "", err
+		}
+	}
+	return p, target, nil
+}
+
+// build writes the whole project into fsys and runs the commands that do
+// not depend on its final location in dir. Commands are skipped when dir is
+// empty because fsys is not on disk.
+func (g *generation) build(fsys FS, dir string) error {
+	if err := fsys.MkdirAll(".", 0755); err != nil {
+		return fmt.Errorf("creating project directory: %w", err)
+	}
+
+	if err := g.runCommands(dir, "pre", g.plan.pre); err != nil {
+		return err
+	}
+
+	for _, d := range g.plan.dirs {
+		if err := fsys.MkdirAll(d, 0755); err != nil {
+			return fmt.Errorf("creating directory %s: %w", d, err)
+		}
+	}
+
+	if err := g.copyFiles(fsys); err != nil {
+		return err
+	}
+	if err := g.runCommands(dir, "post", g.plan.post); err != nil {
+		return err
+	}
+
+	// The lockfile comes last so it can account for the files the
+	// commands wrote.
+	return g.writeLockFile(fsys, dir)
+}
+
+// copyFiles copies every template concurrently and reports all failures together.
+func (g *generation) copyFiles(fsys FS) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	copyErr := &CopyError{Total: len(g.plan.files)}
+	copyCh := make(chan struct{}, maxConcurrentCopies)
+
+	for _, f := range g.plan.files {
+		wg.Add(1)
+		copyCh <- struct{}{}
+		go func(f File) {
+			defer func() {
+				<-copyCh
+				wg.Done()
+			}()
+			if err := g.copyFile(fsys, f); err != nil {
+				mu.Lock()
+				copyErr.Failures = append(copyErr.Failures, FileError{File: f, Err: err})
+				mu.Unlock()
+			}
+		}(f)
+	}
+	wg.Wait()
+
+	sort.Strings(g.result.Files)
+	if len(copyErr.Failures) > 0 {
+		sort.Slice(copyErr.Failures, func(i, j int) bool {
+			return copyErr.Failures[i].Dest < copyErr.Failures[j].Dest
+		})
+		return copyErr
+	}
+	return nil
+}
+
+// copyFile fetches, renders and writes one template.
+func (g *generation) copyFile(fsys FS, f File) error {
+	if err := g.ctx.Err(); err != nil {
+		return err
+	}
+	data, err := fetchTemplate(g.ctx, g.src, f.Template)
+	if err != nil {
+		return err
+	}
+	g.emit(Event{Type: EventTemplateFetched, Template: f.Template, Bytes: len(data)})
+
+	content, err := g.plan.renderFile(f.Template, data)
+	if err != nil {
+		return err
+	}
+
+	if err := fsys.MkdirAll(path.Dir(f.Dest), 0755); err != nil {
+		return err
+	}
+	if err := fsys.WriteFile(f.Dest, content, 0644); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	g.done++
+	done := g.done
+	g.result.Files = append(g.result.Files, f.Dest)
+	g.locked = append(g.locked, LockedFile{Path: f.Dest, Template: f.Template, TemplateHash: HashContent(data), Hash: HashContent(content)})
+	g.mu.Unlock()
+	g.emit(Event{Type: EventFileWritten, Path: f.Dest, Template: f.Template, Bytes: len(content), Done: done, Total: len(g.plan.files)})
+	return nil
+}
+
+// writeLockFile records the provenance of the copied files in the project,
+// and of the files the commands run in dir wrote.
+func (g *generation) writeLockFile(fsys FS, dir string) error {
+	var generated []GeneratedFile
+	if dir != "" && !g.SkipCommands {
+		var err error
+		if generated, err = g.generatedFiles(dir); err != nil {
+			return fmt.Errorf("listing the files written by commands: %w", err)
+		}
+	}
+	data, err := g.lockFile(generated)
+	if err != nil {
+		return err
+	}
+	if err := fsys.WriteFile(LockFileName, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", LockFileName, err)
+	}
+	g.result.Files = append(g.result.Files, LockFileName)
+	sort.Strings(g.result.Files)
+	return nil
+}
+
+// runCommands runs commands in dir, stopping at the first required one
+// that fails. With no dir the commands are only recorded as skipped.
+func (g *generation) runCommands(dir, phase string, commands []Command) error {
+	for _, c := range commands {
+		args, err := g.plan.renderArgs(c)
+		if err != nil {
+			return err
+		}
+		env, err := g.plan.renderEnv(c)
+		if err != nil {
+			return err
+		}
+		if dir == "" || g.SkipCommands {
+			g.result.Commands = append(g.result.Commands, CommandResult{Phase: phase, Args: args, Optional: c.Optional, Skipped: true})
+			continue
+		}
+
+		if len(args) > 0 {
+			args[0] = resolveBinary(g.runner(), args[0])
+		}
+		g.emit(Event{Type: EventCommandStarted, Command: args, Phase: phase})
+		start := time.Now()
+
+		var output bytes.Buffer
+		var out io.Writer = &output
+		if c.Stream {
+			out = io.MultiWriter(g.stdout(), &output)
+		}
+		err = g.runner().Run(g.ctx, Invocation{Args: args, Dir: dir, Env: env, Timeout: g.CommandTimeout, Output: out})
+		if err != nil && g.ctx.Err() != nil {
+			// Killed because generation was cancelled: not the command's
+			// fault, and never worth continuing past, even when optional.
+			err = g.ctx.Err()
+		}
+
+		res := CommandResult{Phase: phase, Args: args, DurationMS: time.Since(start).Milliseconds(), Optional: c.Optional}
+		if err != nil {
+			res.ExitCode = -1
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				res.ExitCode = exitErr.ExitCode()
+			}
+			err = fmt.Errorf("running %s: %w", strings.Join(args, " "), err)
+			if !c.Stream && output.Len() > 0 {
+				err = fmt.Errorf("%w\n%s", err, strings.TrimSpace(output.String()))
+			}
+			res.Error = err.Error()
+		}
+		g.result.Commands = append(g.result.Commands, res)
+		g.emit(Event{Type: EventCommandFinished, Command: args, Phase: phase, DurationMS: res.DurationMS, Error: res.Error})
+
+		if err != nil {
+			if !c.Optional || g.ctx.Err() != nil {
+				return err
+			}
+			fmt.Fprintln(g.stdout(), color.YellowString("Warning:"), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// stubSource serves the embedded index and manifests, and for every
+// project template a one-line file naming it.
+type stubSource struct{}
+
+func (stubSource) Fetch(ctx context.Context, key string) ([]byte, error) {
+	data, err := NewEmbedSource().Fetch(ctx, key)
+	if errors.Is(err, ErrTemplateNotFound) {
+		return []byte("// " + key + "\n"), nil
+	}
+	return data, err
+}
+
+func (stubSource) String() string { return "stub" }
+
+// projectFiles lists the regular files in fsys, and checks dirs exist.
+func projectFiles(t *testing.T, fsys fs.FS, dirs []string) []string {
+	t.Helper()
+	var files []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, p)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range dirs {
+		if info, err := fs.Stat(fsys, d); err != nil || !info.IsDir() {
+			t.Errorf("directory %s was not created", d)
+		}
+	}
+	return files
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     Spec
+		files    []string
+		dirs     []string
+		commands []string
+		// content maps a file to the template it must come from.
+		content map[string]string
+	}{
+		{
+			name:     "gin",
+			spec:     Spec{Language: "go", Name: "svc", Framework: "gin"},
+			files:    []string{LockFileName, "api/api.go", "main.go"},
+			dirs:     []string{"api", "config", "middleware", "models"},
+			commands: []string{"go mod init svc", "go mod tidy"},
+			content:  map[string]string{"main.go": "templates/go/gin/main.txt"},
+		},
+		{
+			name:     "gin with gorm, postgres and docker",
+			spec:     Spec{Language: "go", Name: "svc", Framework: "gin", ORM: "gorm", Database: "postgres", Module: "example.com/svc", Features: []string{"docker"}},
+			files:    []string{LockFileName, ".dockerignore", "Dockerfile", "api/api.go", "config/init_db.go", "main.go"},
+			commands: []string{"go mod init example.com/svc", "go mod tidy"},
+			content:  map[string]string{"config/init_db.go": "templates/go/databases/gorm/init_pg.txt"},
+		},
+		{
+			name:     "express",
+			spec:     Spec{Language: "node", Name: "web", Framework: "express"},
+			files:    []string{LockFileName, "package.json", "src/controllers/userController.js", "src/index.js", "src/models/user.js", "src/routes/user.js"},
+			commands: []string{"npm init -y", "npm pkg set name=web", "npm install --prefer-offline --frozen-lockfile"},
+			content:  map[string]string{"package.json": "templates/node/js/package.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// In memory: the whole tree is written and the commands are
+			// only recorded as skipped.
+			mem := NewMemFS()
+			result, err := (&Generator{Source: stubSource{}, FS: mem}).Generate(ctx, tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if files := projectFiles(t, mem, tt.dirs); !slices.Equal(files, tt.files) {
+				t.Errorf("files = %q, want %q", files, tt.files)
+			}
+			var skipped []string
+			for _, c := range result.Commands {
+				if c.Skipped {
+					skipped = append(skipped, Invocation{Args: c.Args}.String())
+				}
+			}
+			if !slices.Equal(skipped, tt.commands) {
+				t.Errorf("skipped commands = %q, want %q", skipped, tt.commands)
+			}
+			for name, template := range tt.content {
+				if data, _ := fs.ReadFile(mem, name); string(data) != "// "+template+"\n" {
+					t.Errorf("%s = %q, want it rendered from %s", name, data, template)
+				}
+			}
+
+			// On disk: the commands run in order, in the staging
+			// directory, and the project is moved into place.
+			runner := &RecordingRunner{}
+			g := &Generator{Source: stubSource{}, Runner: runner, Dir: t.TempDir()}
+			if _, err := g.Generate(ctx, tt.spec); err != nil {
+				t.Fatal(err)
+			}
+			if commands := runner.Commands(); !slices.Equal(commands, tt.commands) {
+				t.Errorf("commands = %q, want %q", commands, tt.commands)
+			}
+			for _, inv := range runner.Invocations() {
+				if filepath.Base(inv.Dir) != tt.spec.Name {
+					t.Errorf("%s ran in %s, want a directory named %s", inv, inv.Dir, tt.spec.Name)
+				}
+			}
+			dir := filepath.Join(g.Dir, tt.spec.Name)
+			if files := projectFiles(t, os.DirFS(dir), tt.dirs); !slices.Equal(files, tt.files) {
+				t.Errorf("files on disk = %q, want %q", files, tt.files)
+			}
+			lock, err := ReadLockFile(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(lock.Files) != len(tt.files)-1 {
+				t.Errorf("the lockfile records %d files, want %d", len(lock.Files), len(tt.files)-1)
+			}
+		})
+	}
+}
+
+func TestCancelledGenerationLeavesNothingBehind(t *testing.T) {
+	for _, at := range []string{"go mod init svc", "go mod tidy"} {
+		t.Run(at, func(t *testing.T) {
+			dir := t.TempDir()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			runner := &RecordingRunner{Respond: func(inv Invocation) (string, error) {
+				if inv.String() == at {
+					cancel()
+					return "", context.Canceled
+				}
+				return "", nil
+			}}
+			g := &Generator{Source: stubSource{}, Runner: runner, Dir: dir}
+			_, err := g.Generate(ctx, Spec{Language: "go", Name: "svc", Framework: "gin"})
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("err = %v, want context.Canceled", err)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("left behind in %s: %v", dir, entries)
+			}
+		})
+	}
+}
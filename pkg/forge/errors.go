@@ -0,0 +1,35 @@
+package forge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileError is a template that could not be fetched, rendered or written.
+type FileError struct {
+	File
+	Err error
+}
+
+// CopyError lists every template that failed during one generation.
+type CopyError struct {
+	Failures []FileError
+	Total    int
+}
+
+func (e *CopyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d files could not be generated:", len(e.Failures), e.Total)
+	for _, f := range e.Failures {
+		fmt.Fprintf(&b, "\n  %s (%s): %v", f.Dest, f.Template, f.Err)
+	}
+	return b.String()
+}
+
+func (e *CopyError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
@@ -0,0 +1,57 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// failingSource serves stubSource, except for the project templates of
+// one pack, which are all missing.
+type failingSource struct {
+	stubSource
+	prefix string
+}
+
+func (s failingSource) Fetch(ctx context.Context, key string) ([]byte, error) {
+	if strings.HasPrefix(key, s.prefix) && strings.HasSuffix(key, ".txt") {
+		return nil, ErrTemplateNotFound
+	}
+	return s.stubSource.Fetch(ctx, key)
+}
+
+func TestCopyErrorReportsEveryFailure(t *testing.T) {
+	dir := t.TempDir()
+	g := &Generator{Source: failingSource{prefix: "templates/go/gin/"}, Runner: &RecordingRunner{}, Dir: dir}
+	_, err := g.Generate(context.Background(), Spec{Language: "go", Name: "svc", Framework: "gin"})
+
+	var copyErr *CopyError
+	if !errors.As(err, &copyErr) {
+		t.Fatalf("err = %v, want a *CopyError", err)
+	}
+	var dests []string
+	for _, f := range copyErr.Failures {
+		dests = append(dests, f.Dest)
+	}
+	slices.Sort(dests)
+	if want := []string{"api/api.go", "main.go"}; !slices.Equal(dests, want) {
+		t.Errorf("failures = %v, want %v", dests, want)
+	}
+	if copyErr.Total < len(copyErr.Failures) {
+		t.Errorf("total = %d, less than the %d failures", copyErr.Total, len(copyErr.Failures))
+	}
+	if !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("err does not wrap ErrTemplateNotFound: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("generation was not rolled back: %s holds %v", dir, entries)
+	}
+}
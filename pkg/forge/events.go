@@ -0,0 +1,77 @@
+package forge
+
+import (
+	"time"
+)
+
+// Event types emitted while a project is generated.
+const (
+	EventGenerationStarted  = "generation_started"
+	EventTemplateFetched    = "template_fetched"
+	EventFileWritten        = "file_written"
+	EventCommandStarted     = "command_started"
+	EventCommandFinished    = "command_finished"
+	EventGenerationFinished = "generation_finished"
+)
+
+// Event reports progress during generation. Done and Total count written
+// files so a UI can drive a progress bar.
+type Event struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Project    string    `json:"project,omitempty"`
+	Path       string    `json:"path,omitempty"`
+	Template   string    `json:"template,omitempty"`
+	Bytes      int       `json:"bytes,omitempty"`
+	Command    []string  `json:"command,omitempty"`
+	Phase      string    `json:"phase,omitempty"`
+	Done       int       `json:"done,omitempty"`
+	Total      int       `json:"total,omitempty"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Result summarises a finished generation, successful or not.
+type Result struct {
+	ProjectPath string          `json:"project_path"`
+	Archive     string          `json:"archive,omitempty"`
+	Language    string          `json:"language"`
+	Framework   string          `json:"framework"`
+	Database    string          `json:"database,omitempty"`
+	ORM         string          `json:"orm,omitempty"`
+	TypeScript  bool            `json:"typescript,omitempty"`
+	Features    []string        `json:"features,omitempty"`
+	Files       []string        `json:"files"`
+	Merge       []MergeEntry    `json:"merge,omitempty"`
+	Commands    []CommandResult `json:"commands"`
+	DurationMS  int64           `json:"duration_ms"`
+	Success     bool            `json:"success"`
+	Errors      []string        `json:"errors,omitempty"`
+}
+
+// NewResult returns the Result of generating spec before anything has been
+// done. It also reports errors that stop generation from starting.
+func NewResult(spec Spec) *Result {
+	return &Result{
+		ProjectPath: spec.Name,
+		Language:    spec.Language,
+		Framework:   spec.Framework,
+		Database:    spec.Database,
+		ORM:         spec.ORM,
+		TypeScript:  spec.TypeScript,
+		Features:    spec.Features,
+		Files:       []string{},
+		Commands:    []CommandResult{},
+	}
+}
+
+// CommandResult records one external command run during generation.
+type CommandResult struct {
+	Phase      string   `json:"phase"`
+	Args       []string `json:"args"`
+	ExitCode   int      `json:"exit_code"`
+	DurationMS int64    `json:"duration_ms"`
+	Optional   bool     `json:"optional,omitempty"`
+	Skipped    bool     `json:"skipped,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
@@ -0,0 +1,95 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden compares got with testdata/name, or rewrites it with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v; run go test -update to create it", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file; run go test -update if the change is intended:\n%s",
+			name, unifiedDiff("want/"+name, "got/"+name, want, got))
+	}
+}
+
+func TestEventsAndResultGolden(t *testing.T) {
+	dir := t.TempDir()
+	var events []Event
+	g := &Generator{Source: stubSource{}, Runner: goModRunner(), Dir: dir, Events: func(e Event) { events = append(events, e) }}
+	result, err := g.Generate(context.Background(), Spec{Language: "go", Name: "svc", Framework: "gin", Features: []string{"docker"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Files are fetched and written concurrently: order each run of file
+	// events by type and template, and check the progress counts separately.
+	isFile := func(e Event) bool { return e.Type == EventTemplateFetched || e.Type == EventFileWritten }
+	var done []int
+	for i := 0; i < len(events); {
+		j := i
+		for j < len(events) && isFile(events[j]) {
+			if events[j].Type == EventFileWritten {
+				done = append(done, events[j].Done)
+			}
+			events[j].Done = 0
+			j++
+		}
+		slices.SortStableFunc(events[i:j], func(a, b Event) int {
+			return strings.Compare(a.Type+" "+a.Template, b.Type+" "+b.Template)
+		})
+		i = max(j, i+1)
+	}
+	slices.Sort(done)
+	for i, d := range done {
+		if d != i+1 {
+			t.Errorf("file_written done counts = %v, want 1 to %d", done, len(done))
+			break
+		}
+	}
+
+	var ndjson bytes.Buffer
+	enc := json.NewEncoder(&ndjson)
+	for _, e := range events {
+		e.Time, e.DurationMS = time.Time{}, 0
+		enc.Encode(e)
+	}
+	checkGolden(t, "events.ndjson", normalizePaths(ndjson.Bytes(), dir))
+
+	result.DurationMS = 0
+	for i := range result.Commands {
+		result.Commands[i].DurationMS = 0
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "result.json", normalizePaths(append(data, '\n'), dir))
+}
+
+// normalizePaths replaces dir in data with $DIR.
+func normalizePaths(data []byte, dir string) []byte {
+	return bytes.ReplaceAll(data, []byte(filepath.ToSlash(dir)), []byte("$DIR"))
+}
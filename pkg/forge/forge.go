@@ -0,0 +1,125 @@
+// Package forge generates backend project scaffolds from template packs.
+//
+// A Spec says what to generate; a Generator says where templates come
+// from, where files go and how external commands run:
+//
+//	g := &forge.Generator{Source: forge.NewS3Source(forge.DefaultBucket, "")}
+//	result, err := g.Generate(ctx, forge.Spec{Language: "go", Name: "myapp", Framework: "gin"})
+package forge
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Spec describes the project to generate. It is what a backendforger.yaml
+// spec file holds.
+type Spec struct {
+	Language   string   `json:"language" yaml:"language"`
+	Name       string   `json:"name" yaml:"name,omitempty"`
+	Framework  string   `json:"framework" yaml:"framework"`
+	Database   string   `json:"database,omitempty" yaml:"database,omitempty"`
+	ORM        string   `json:"orm,omitempty" yaml:"orm,omitempty"`
+	TypeScript bool     `json:"typescript,omitempty" yaml:"typescript,omitempty"`
+	Features   []string `json:"features,omitempty" yaml:"features,omitempty"`
+	// Module is the Go module path, npm package name or Python package
+	// name; defaults to Name. It replaces "yourapp" in the templates.
+	Module string `json:"module,omitempty" yaml:"module,omitempty"`
+	// TemplateVersion, if set, must match the version of the framework's
+	// manifest so a spec never silently generates from other templates.
+	TemplateVersion string `json:"template_version,omitempty" yaml:"template_version,omitempty"`
+}
+
+// Generator turns a Spec into a project. The zero value reads templates
+// from the default S3 bucket, writes to a directory named after the project
+// under the working directory and runs commands with exec.
+type Generator struct {
+	// Source provides templates and manifests.
+	Source TemplateSource
+	// Registry, if set, is the registry of Source. Long-lived callers set
+	// it so the catalog and manifests are not read again for every project.
+	Registry *Registry
+	// Runner runs the manifest's commands. Defaults to ExecRunner.
+	Runner CommandRunner
+	// CommandTimeout, if positive, bounds each command the manifest runs.
+	CommandTimeout time.Duration
+	// FS, if set, receives the generated files instead of the disk. Paths
+	// are relative to the project root and no commands are run, since they
+	// need a real directory.
+	FS FS
+	// Dir is the directory the project directory is created in. Defaults to ".".
+	Dir string
+
+	// Existing says what to do when the project directory already exists
+	// and is not empty. The zero value refuses to write into it.
+	Existing ExistingPolicy
+	// Diffs records a unified diff in Result.Merge for every existing file
+	// that differs from the generated one.
+	Diffs bool
+
+	// SkipCommands records the manifest's commands (go mod init, npm
+	// install, ...) as skipped instead of running them, so the tools they
+	// need do not have to be installed.
+	SkipCommands bool
+
+	// KeepOnFailure leaves the staging directory behind for debugging
+	// instead of removing it when generation fails.
+	KeepOnFailure bool
+
+	// Stdout receives the human-readable messages and the output of
+	// streamed commands. Defaults to io.Discard.
+	Stdout io.Writer
+	// Events, if set, is called for every progress event. Calls are serialised.
+	Events func(Event)
+
+	// archiving is set by Archive: the project on disk is temporary, so
+	// after-commit commands and the summary are skipped.
+	archiving bool
+}
+
+// Generate creates the project described by spec with a default Generator.
+func Generate(ctx context.Context, spec Spec) (*Result, error) {
+	return (&Generator{}).Generate(ctx, spec)
+}
+
+func (g *Generator) source() TemplateSource {
+	if g.Source == nil {
+		return NewS3Source(DefaultBucket, "")
+	}
+	return g.Source
+}
+
+func (g *Generator) runner() CommandRunner {
+	if g.Runner == nil {
+		return ExecRunner{}
+	}
+	return g.Runner
+}
+
+func (g *Generator) stdout() io.Writer {
+	if g.Stdout == nil {
+		return io.Discard
+	}
+	return g.Stdout
+}
+
+// FS is a destination for generated files.
+type FS interface {
+	MkdirAll(path string, perm fs.FileMode) error
+	WriteFile(path string, data []byte, perm fs.FileMode) error
+}
+
+// DirFS writes files below a directory on disk.
+type DirFS string
+
+func (d DirFS) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(filepath.Join(string(d), filepath.FromSlash(path)), perm)
+}
+
+func (d DirFS) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(filepath.Join(string(d), filepath.FromSlash(path)), data, perm)
+}
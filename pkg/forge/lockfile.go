@@ -0,0 +1,174 @@
+package forge
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Version is the version of backendforger recorded in lockfiles. Release
+// builds set it with -ldflags "-X github.com/TheRSTech/Backendforger-backend/pkg/forge.Version=v1.2.3".
+var Version = "dev"
+
+// LockFileName is the provenance lockfile written at the root of every
+// generated project.
+const LockFileName = ".backendforger.lock"
+
+// LockFileVersion is the format version written to new lockfiles.
+const LockFileVersion = 1
+
+// Lock records how a project was generated: the spec, pinned to the
+// template version used, the backendforger version and, for every file,
+// the template it came from and hashes of the template and of the rendered
+// output. It is enough to render the same project again and to tell which
+// files were changed since.
+type Lock struct {
+	Version        int          `json:"version"`
+	Generator      string       `json:"generator"`
+	GeneratedAt    time.Time    `json:"generated_at"`
+	TemplateSource string       `json:"template_source"`
+	Spec           Spec         `json:"spec"`
+	Files          []LockedFile `json:"files"`
+	// Generated lists the files the setup commands created or changed,
+	// such as go.mod or package-lock.json, as they left them.
+	Generated []GeneratedFile `json:"generated,omitempty"`
+	// Resources lists the resources added with AddResource, in order.
+	Resources []LockedResource `json:"resources,omitempty"`
+}
+
+// LockedResource is a resource in a Lock: the files AddResource rendered
+// for it and the existing files it edited, as it left them.
+type LockedResource struct {
+	Resource
+	Files  []LockedFile    `json:"files"`
+	Edited []GeneratedFile `json:"edited,omitempty"`
+}
+
+// GeneratedFile is a file written by a setup command rather than rendered
+// from a template, or a rendered file a command changed afterwards. Hash is
+// that of its contents once the commands had run.
+type GeneratedFile struct {
+	Path string `json:"path"`
+	Hash string `json:"hash"`
+}
+
+// LockedFile is one generated file in a Lock. Kept marks a file that
+// already existed, differed from the template's output and was kept when the
+// project was generated into an existing directory; its Hash is then that
+// of the file found on disk. Conflict marks a file an upgrade wrote
+// conflict markers into, until the next upgrade finds them resolved.
+type LockedFile struct {
+	Path         string `json:"path"`
+	Template     string `json:"template"`
+	TemplateHash string `json:"template_hash"`
+	Hash         string `json:"hash"`
+	Kept         bool   `json:"kept,omitempty"`
+	Conflict     bool   `json:"conflict,omitempty"`
+}
+
+// File returns the entry for path, or nil.
+func (l *Lock) File(path string) *LockedFile {
+	for i := range l.Files {
+		if l.Files[i].Path == path {
+			return &l.Files[i]
+		}
+	}
+	return nil
+}
+
+// ReadLockFile reads the lockfile of the project in dir.
+func ReadLockFile(dir string) (*Lock, error) {
+	data, err := os.ReadFile(filepath.Join(dir, LockFileName))
+	if err != nil {
+		return nil, err
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", LockFileName, err)
+	}
+	if lock.Version > LockFileVersion {
+		return nil, fmt.Errorf("%s has format version %d; this backendforger only understands up to %d", LockFileName, lock.Version, LockFileVersion)
+	}
+	return &lock, nil
+}
+
+// HashContent returns the hash recorded in lockfiles for data.
+func HashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// generatedFiles lists the files in dir that differ from the rendered
+// templates, that is the ones the commands created or changed. Dependency
+// and VCS directories are skipped, as in Diff.
+func (g *generation) generatedFiles(dir string) ([]GeneratedFile, error) {
+	rendered := map[string]string{}
+	g.mu.Lock()
+	for _, f := range g.locked {
+		rendered[f.Path] = f.Hash
+	}
+	g.mu.Unlock()
+
+	var generated []GeneratedFile
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && slices.Contains(driftIgnoredDirs, d.Name()) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if hash := HashContent(data); rel != LockFileName && rendered[rel] != hash {
+			generated = append(generated, GeneratedFile{Path: rel, Hash: hash})
+		}
+		return nil
+	})
+	return generated, err
+}
+
+// lockFile builds the lockfile of the generation from the files written so
+// far and those the commands generated.
+func (g *generation) lockFile(generated []GeneratedFile) ([]byte, error) {
+	spec := g.spec
+	spec.TemplateVersion = g.plan.version
+	g.mu.Lock()
+	files := append([]LockedFile{}, g.locked...)
+	g.mu.Unlock()
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	lock := Lock{
+		Version:        LockFileVersion,
+		Generator:      "backendforger " + Version,
+		GeneratedAt:    time.Now().UTC().Truncate(time.Second),
+		TemplateSource: g.src.String(),
+		Spec:           spec,
+		Files:          files,
+		Generated:      generated,
+	}
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
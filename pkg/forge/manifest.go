@@ -0,0 +1,218 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Manifest describes a framework's template pack: the directories and files
+// it generates, the commands run around them and the hints printed at the end.
+// It is published next to the templates as templates/<language>/<framework>/manifest.json.
+type Manifest struct {
+	Language    string `json:"language"`
+	Framework   string `json:"framework"`
+	Description string `json:"description,omitempty"`
+	// Version identifies the revision of the templates; bump it on changes.
+	Version string `json:"version,omitempty"`
+	// Combinations lists every supported ORM with the databases it works
+	// with. It is the single source of truth for validation and listings.
+	Combinations []Combination `json:"combinations"`
+
+	// Placeholders maps literal text in legacy (non-.tmpl) templates to the
+	// value replacing it, itself rendered like a template, e.g. "[[ .ProjectName ]]".
+	Placeholders map[string]string `json:"placeholders,omitempty"`
+	// Delimiters overrides DefaultDelimiters for this pack.
+	Delimiters *[2]string `json:"delimiters,omitempty"`
+	Features   []Feature  `json:"features,omitempty"`
+
+	Directories  []Directory `json:"directories,omitempty"`
+	Files        []File      `json:"files"`
+	PreCommands  []Command   `json:"pre_commands,omitempty"`
+	PostCommands []Command   `json:"post_commands,omitempty"`
+	NextSteps    []NextStep  `json:"next_steps,omitempty"`
+
+	// Resource, if set, lets 'add resource' generate CRUD resources in
+	// projects made from this pack.
+	Resource *ResourcePack `json:"resource,omitempty"`
+}
+
+// ResourcePack describes a CRUD resource: the files generated for it and
+// the edits that wire it into the project. Everything in it is rendered
+// with a ResourceContext.
+type ResourcePack struct {
+	Files     []File     `json:"files"`
+	Edits     []Edit     `json:"edits,omitempty"`
+	NextSteps []NextStep `json:"next_steps,omitempty"`
+}
+
+// Edit inserts Insert into an existing project file: after the first run of
+// lines matching one of the After regular expressions, such as the imports,
+// else before the first line matching one of Before, indented like that line. Vars are regular
+// expressions searched in the file whose first non-empty group becomes
+// .Vars.<name>, e.g. the name of the router variable. InFunc restricts the
+// anchors of an edit of a Go file to lines inside a function body. An edit
+// whose lines are already in the file is skipped; one that cannot be placed
+// is left to the user, along with the other edits of its file.
+type Edit struct {
+	File   string            `json:"file"`
+	Vars   map[string]string `json:"vars,omitempty"`
+	After  []string          `json:"after,omitempty"`
+	Before []string          `json:"before,omitempty"`
+	InFunc bool              `json:"in_func,omitempty"`
+	Insert string            `json:"insert"`
+	When   *Condition        `json:"when,omitempty"`
+}
+
+// Combination is an ORM and the databases it supports. An empty ORM stands
+// for generating without --orm.
+type Combination struct {
+	ORM       string   `json:"orm"`
+	Databases []string `json:"databases,omitempty"`
+	// DefaultDatabase is what the templates use when no database is given.
+	// Without it, a combination that has Databases requires one.
+	DefaultDatabase string `json:"default_database,omitempty"`
+	// TypeScript, if set, restricts the combination to TypeScript or JavaScript projects.
+	TypeScript *bool `json:"typescript,omitempty"`
+}
+
+// Feature is an optional part of a template pack enabled with --feature.
+type Feature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Condition restricts an entry to some project options. Every non-empty
+// field must match; a nil condition always matches.
+type Condition struct {
+	Database    []string `json:"database,omitempty"`
+	NotDatabase []string `json:"database_not,omitempty"`
+	ORM         []string `json:"orm,omitempty"`
+	NotORM      []string `json:"orm_not,omitempty"`
+	TypeScript  *bool    `json:"typescript,omitempty"`
+	Feature     []string `json:"feature,omitempty"`
+}
+
+// Directory is a directory created inside the project.
+type Directory struct {
+	Path string     `json:"path"`
+	When *Condition `json:"when,omitempty"`
+}
+
+// File is a template key rendered to a path inside the project. Templates
+// ending in .tmpl are rendered with text/template and a RenderContext; any
+// other template only gets the manifest's placeholders replaced.
+type File struct {
+	Template string     `json:"template"`
+	Dest     string     `json:"dest"`
+	When     *Condition `json:"when,omitempty"`
+}
+
+// Command is an external command run in the project directory.
+// Failures of optional commands are reported but do not stop generation.
+// AfterCommit post-commands run once the project is in its final location,
+// for tools such as venv that record absolute paths; they cannot be rolled back.
+// Env values are rendered like the arguments.
+type Command struct {
+	Run         []string          `json:"run"`
+	Env         map[string]string `json:"env,omitempty"`
+	Optional    bool              `json:"optional,omitempty"`
+	Stream      bool              `json:"stream,omitempty"`
+	AfterCommit bool              `json:"after_commit,omitempty"`
+	When        *Condition        `json:"when,omitempty"`
+}
+
+// NextStep is printed after a successful generation.
+type NextStep struct {
+	Description string     `json:"description"`
+	Commands    []string   `json:"commands,omitempty"`
+	When        *Condition `json:"when,omitempty"`
+}
+
+// Databases returns every database supported by some combination.
+func (m *Manifest) Databases() []string {
+	var dbs []string
+	for _, c := range m.Combinations {
+		for _, db := range c.Databases {
+			if !slices.Contains(dbs, db) {
+				dbs = append(dbs, db)
+			}
+		}
+	}
+	return dbs
+}
+
+// ORMs returns every ORM that can be passed with --orm.
+func (m *Manifest) ORMs() []string {
+	var orms []string
+	for _, c := range m.Combinations {
+		if c.ORM != "" && !slices.Contains(orms, c.ORM) {
+			orms = append(orms, c.ORM)
+		}
+	}
+	return orms
+}
+
+// HasFeature reports whether the pack offers the named feature.
+func (m *Manifest) HasFeature(name string) bool {
+	return slices.ContainsFunc(m.Features, func(f Feature) bool { return f.Name == name })
+}
+
+func (m *Manifest) delimiters() [2]string {
+	if m.Delimiters != nil {
+		return *m.Delimiters
+	}
+	return DefaultDelimiters
+}
+
+func manifestKey(language, framework string) string {
+	return fmt.Sprintf("templates/%s/%s/manifest.json", language, framework)
+}
+
+// LoadManifest reads the manifest for a framework from src.
+func LoadManifest(ctx context.Context, src TemplateSource, language, framework string) (*Manifest, error) {
+	key := manifestKey(language, framework)
+	data, err := fetchMetadata(ctx, src, key)
+	if errors.Is(err, ErrTemplateNotFound) {
+		return nil, fmt.Errorf("unsupported %s framework %q", language, framework)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", key, err)
+	}
+	return &m, nil
+}
+
+// Matches reports whether the condition holds for spec.
+func (c *Condition) Matches(spec Spec) bool {
+	if c == nil {
+		return true
+	}
+	if len(c.Database) > 0 && !slices.Contains(c.Database, spec.Database) {
+		return false
+	}
+	if slices.Contains(c.NotDatabase, spec.Database) {
+		return false
+	}
+	if len(c.ORM) > 0 && !slices.Contains(c.ORM, spec.ORM) {
+		return false
+	}
+	if slices.Contains(c.NotORM, spec.ORM) {
+		return false
+	}
+	if c.TypeScript != nil && *c.TypeScript != spec.TypeScript {
+		return false
+	}
+	for _, f := range c.Feature {
+		if !slices.Contains(spec.Features, f) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package forge
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConditionMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		when string
+		spec Spec
+		want bool
+	}{
+		{"nil", "null", Spec{}, true},
+		{"empty", "{}", Spec{Database: "postgres"}, true},
+		{"database", `{"database": ["postgres", "mysql"]}`, Spec{Database: "mysql"}, true},
+		{"other database", `{"database": ["postgres"]}`, Spec{Database: "sqlite"}, false},
+		{"no database", `{"database": ["postgres"]}`, Spec{}, false},
+		{"database_not", `{"database_not": ["sqlite"]}`, Spec{Database: "postgres"}, true},
+		{"excluded database", `{"database_not": ["sqlite"]}`, Spec{Database: "sqlite"}, false},
+		{"orm", `{"orm": ["gorm"]}`, Spec{ORM: "gorm"}, true},
+		{"without orm", `{"orm": ["gorm"]}`, Spec{}, false},
+		{"orm_not", `{"orm_not": ["gorm"]}`, Spec{}, true},
+		{"excluded orm", `{"orm_not": ["gorm"]}`, Spec{ORM: "gorm"}, false},
+		{"typescript", `{"typescript": true}`, Spec{TypeScript: true}, true},
+		{"javascript", `{"typescript": false}`, Spec{TypeScript: true}, false},
+		{"feature", `{"feature": ["docker"]}`, Spec{Features: []string{"ci", "docker"}}, true},
+		{"missing feature", `{"feature": ["docker", "ci"]}`, Spec{Features: []string{"docker"}}, false},
+		{"all fields", `{"database": ["postgres"], "orm": ["gorm"], "feature": ["docker"]}`, Spec{Database: "postgres", ORM: "gorm", Features: []string{"docker"}}, true},
+		{"one field fails", `{"database": ["postgres"], "orm": ["gorm"]}`, Spec{Database: "postgres", ORM: "ent"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c *Condition
+			if err := json.Unmarshal([]byte(tt.when), &c); err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Matches(tt.spec); got != tt.want {
+				t.Errorf("%s.Matches(%+v) = %v, want %v", tt.when, tt.spec, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package forge
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemFS is an in-memory FS. It also implements fs.FS so the generated
+// project can be read back or archived. It is safe for concurrent use.
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+}
+
+// memFile is a file or directory of a MemFS.
+type memFile struct {
+	name    string
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// NewMemFS returns an empty MemFS.
+func NewMemFS() *MemFS {
+	return &MemFS{files: map[string]*memFile{}}
+}
+
+func (m *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mkdirAll(path.Clean(name), perm)
+	return nil
+}
+
+// mkdirAll adds name and its parents. The caller holds m.mu.
+func (m *MemFS) mkdirAll(name string, perm fs.FileMode) {
+	for ; name != "." && name != "/"; name = path.Dir(name) {
+		if _, ok := m.files[name]; !ok {
+			m.files[name] = &memFile{name: path.Base(name), mode: fs.ModeDir | perm, modTime: time.Now()}
+		}
+	}
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = path.Clean(name)
+	m.mkdirAll(path.Dir(name), 0755)
+	m.files[name] = &memFile{name: path.Base(name), data: slices.Clone(data), mode: perm, modTime: time.Now()}
+	return nil
+}
+
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if name == "." {
+		return &memDir{info: &memFile{name: ".", mode: fs.ModeDir | 0755}, entries: m.children(name)}, nil
+	}
+	f, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if f.mode.IsDir() {
+		return &memDir{info: f, entries: m.children(name)}, nil
+	}
+	return &memOpenFile{info: f, Reader: bytes.NewReader(f.data)}, nil
+}
+
+// children lists the entries of the directory dir, sorted by name. The
+// caller holds m.mu.
+func (m *MemFS) children(dir string) []fs.DirEntry {
+	var entries []fs.DirEntry
+	for name, f := range m.files {
+		if path.Dir(name) == dir {
+			entries = append(entries, fs.FileInfoToDirEntry(f))
+		}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries
+}
+
+func (f *memFile) Name() string       { return f.name }
+func (f *memFile) Size() int64        { return int64(len(f.data)) }
+func (f *memFile) Mode() fs.FileMode  { return f.mode }
+func (f *memFile) ModTime() time.Time { return f.modTime }
+func (f *memFile) IsDir() bool        { return f.mode.IsDir() }
+func (f *memFile) Sys() any           { return nil }
+
+// memOpenFile is a regular file of a MemFS opened for reading.
+type memOpenFile struct {
+	*bytes.Reader
+	info *memFile
+}
+
+func (f *memOpenFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memOpenFile) Close() error               { return nil }
+
+// memDir is a directory of a MemFS opened for reading.
+type memDir struct {
+	info    *memFile
+	entries []fs.DirEntry
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
@@ -0,0 +1,48 @@
+package forge
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMemFS(t *testing.T) {
+	m := NewMemFS()
+	if err := m.MkdirAll("empty/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"main.go", "api/api.go", "api/v1/routes.go"} {
+		if err := m.WriteFile(name, []byte("package "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fstest.TestFS(m, "main.go", "api/api.go", "api/v1/routes.go", "empty/dir"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteArchiveFromMemFS(t *testing.T) {
+	m := NewMemFS()
+	m.WriteFile("cmd/run.sh", []byte("#!/bin/sh\n"), 0755)
+	m.WriteFile("go.mod", []byte("module svc\n"), 0644)
+
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, ArchiveZip, m, "svc"); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	modes := map[string]string{}
+	for _, f := range zr.File {
+		modes[f.Name] = f.Mode().String()
+	}
+	want := map[string]string{"svc/cmd/": "drwxr-xr-x", "svc/cmd/run.sh": "-rwxr-xr-x", "svc/go.mod": "-rw-r--r--"}
+	for name, mode := range want {
+		if modes[name] != mode {
+			t.Errorf("%s: mode %q, want %q (entries %v)", name, modes[name], mode, modes)
+		}
+	}
+}
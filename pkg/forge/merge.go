@@ -0,0 +1,306 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ExistingPolicy says what Generate does when the project directory already
+// exists and is not empty.
+type ExistingPolicy int
+
+const (
+	// RefuseExisting fails with a *TargetExistsError.
+	RefuseExisting ExistingPolicy = iota
+	// OverwriteExisting replaces the files the project generates and keeps
+	// every other file.
+	OverwriteExisting
+	// MergeExisting only writes the files that are missing; differing
+	// files are kept and reported as conflicts.
+	MergeExisting
+)
+
+// TargetExistsError is returned when the project directory is not empty
+// and the generator refuses to write into it.
+type TargetExistsError struct {
+	Path string
+}
+
+func (e *TargetExistsError) Error() string {
+	return fmt.Sprintf("directory %s already exists and is not empty", e.Path)
+}
+
+// Actions recorded for each file written into an existing project.
+const (
+	MergeCreated     = "created"
+	MergeSkipped     = "skipped"
+	MergeOverwritten = "overwritten"
+	MergeConflict    = "conflict"
+)
+
+// MergeEntry reports what happened to one generated file when the project
+// directory already existed. Directories that did not exist yet are
+// reported once, with a trailing slash. Diff is set for conflicting and
+// overwritten files when Generator.Diffs is set, and Reason explains
+// conflicts that are never overwritten.
+type MergeEntry struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+// checkTarget refuses to generate into a path that is a file, or into a
+// non-empty directory unless policy allows it. The lockfile of an existing
+// project must be readable, since it is merged only after the generated
+// files have been moved in.
+func checkTarget(target string, policy ExistingPolicy) error {
+	info, err := os.Stat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s already exists and is not a directory", target)
+	}
+	if policy != RefuseExisting {
+		if _, err := ReadLockFile(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("reading the existing %s: %w", LockFileName, err)
+		}
+		return nil
+	}
+	empty, err := isEmptyDir(target)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return &TargetExistsError{Path: target}
+	}
+	return nil
+}
+
+func isEmptyDir(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	return len(entries) == 0, err
+}
+
+// commit moves the staged project to target. An empty or missing target is
+// replaced; an existing project is merged into according to g.Existing.
+func (g *generation) commit(staging, target string) error {
+	empty, err := isEmptyDir(target)
+	if err != nil {
+		return err
+	}
+	if empty {
+		if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return os.Rename(staging, target)
+	}
+	return g.merge(staging, target)
+}
+
+// merge moves every staged file that target lacks into it and compares the
+// others, replacing them only with OverwriteExisting. The lockfile is merged
+// with the existing one by mergeLock.
+func (g *generation) merge(staging, target string) error {
+	overwrite := g.Existing == OverwriteExisting
+	kept := map[string]bool{}
+	err := filepath.WalkDir(staging, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(staging, p)
+		if err != nil || rel == "." || rel == LockFileName {
+			return err
+		}
+		dest := filepath.Join(target, rel)
+		name := filepath.ToSlash(rel)
+		if d.IsDir() {
+			name += "/"
+		}
+
+		existing, err := os.Lstat(dest)
+		if errors.Is(err, fs.ErrNotExist) {
+			if err := os.Rename(p, dest); err != nil {
+				return err
+			}
+			g.addMerge(MergeEntry{Path: name, Action: MergeCreated})
+			return skipDir(d)
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && existing.IsDir() {
+			return nil
+		}
+		if d.IsDir() != existing.IsDir() {
+			// Replacing a directory with a file, or a file with a directory,
+			// would delete the user's data even with OverwriteExisting.
+			reason := "a file is in the way of the generated directory"
+			if existing.IsDir() {
+				reason = "a directory is in the way of the generated file"
+			}
+			g.addMerge(MergeEntry{Path: name, Action: MergeConflict, Reason: reason})
+			kept[name] = true
+			return skipDir(d)
+		}
+
+		same, diff, err := g.compare(p, dest, d, existing, name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case same:
+			g.addMerge(MergeEntry{Path: name, Action: MergeSkipped})
+		case overwrite:
+			if err := os.Remove(dest); err != nil {
+				return err
+			}
+			if err := os.Rename(p, dest); err != nil {
+				return err
+			}
+			g.addMerge(MergeEntry{Path: name, Action: MergeOverwritten, Diff: diff})
+		default:
+			g.addMerge(MergeEntry{Path: name, Action: MergeConflict, Diff: diff})
+			kept[name] = true
+		}
+		return skipDir(d)
+	})
+	if err != nil {
+		return err
+	}
+	return g.mergeLock(staging, target, kept)
+}
+
+// mergeLock writes the lockfile of a project generated into an existing
+// one. Entries of the earlier generation survive unless this one wrote the
+// file, and its features are kept, so files such as a Dockerfile stay
+// accounted for when a later run leaves the feature out. Files that were
+// kept because they conflicted keep their earlier entry or, if there is
+// none, are recorded as found on disk; files behind a kept directory or a
+// file in the way of one are left out.
+func (g *generation) mergeLock(staging, target string, kept map[string]bool) error {
+	lock, err := ReadLockFile(staging)
+	if err != nil {
+		return err
+	}
+	old, err := ReadLockFile(target)
+	existed := err == nil
+	if errors.Is(err, fs.ErrNotExist) {
+		old, err = &Lock{}, nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading the existing %s: %w", LockFileName, err)
+	}
+
+	files := []LockedFile{}
+	for _, f := range lock.Files {
+		switch {
+		case !kept[f.Path] && !keptBelow(kept, f.Path):
+			files = append(files, f)
+		case old.File(f.Path) != nil:
+			files = append(files, *old.File(f.Path))
+		case kept[f.Path]:
+			data, err := os.ReadFile(filepath.Join(target, filepath.FromSlash(f.Path)))
+			if err != nil {
+				// A directory is in the way; it is no generated file.
+				continue
+			}
+			f.Hash, f.Kept = HashContent(data), true
+			files = append(files, f)
+		}
+	}
+	for _, f := range old.Files {
+		if !slices.ContainsFunc(files, func(e LockedFile) bool { return e.Path == f.Path }) {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	lock.Files = files
+	for _, f := range old.Generated {
+		if !slices.ContainsFunc(lock.Generated, func(e GeneratedFile) bool { return e.Path == f.Path }) {
+			lock.Generated = append(lock.Generated, f)
+		}
+	}
+	sort.Slice(lock.Generated, func(i, j int) bool { return lock.Generated[i].Path < lock.Generated[j].Path })
+	for _, f := range old.Spec.Features {
+		if !slices.Contains(lock.Spec.Features, f) {
+			lock.Spec.Features = append(lock.Spec.Features, f)
+		}
+	}
+
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(target, LockFileName), append(data, '\n')); err != nil {
+		return err
+	}
+	action := MergeCreated
+	if existed {
+		action = MergeOverwritten
+	}
+	g.addMerge(MergeEntry{Path: LockFileName, Action: action})
+	return nil
+}
+
+// keptBelow reports whether path lies in a directory that was kept instead
+// of the generated one, or below a file in the way of a generated directory.
+func keptBelow(kept map[string]bool, path string) bool {
+	for dir := range kept {
+		if strings.HasSuffix(dir, "/") && strings.HasPrefix(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare reports whether the staged entry at p matches the existing one
+// at dest and, when g.Diffs is set, how they differ.
+func (g *generation) compare(p, dest string, d fs.DirEntry, existing fs.FileInfo, name string) (bool, string, error) {
+	if d.Type()&fs.ModeSymlink != 0 || existing.Mode()&fs.ModeSymlink != 0 {
+		newLink, _ := os.Readlink(p)
+		oldLink, _ := os.Readlink(dest)
+		return d.Type() == existing.Mode().Type() && newLink == oldLink, "", nil
+	}
+	generated, err := os.ReadFile(p)
+	if err != nil {
+		return false, "", err
+	}
+	current, err := os.ReadFile(dest)
+	if err != nil {
+		return false, "", err
+	}
+	if bytes.Equal(generated, current) {
+		return true, "", nil
+	}
+	if !g.Diffs {
+		return false, "", nil
+	}
+	return false, unifiedDiff("existing/"+name, "generated/"+name, current, generated), nil
+}
+
+func (g *generation) addMerge(e MergeEntry) {
+	g.result.Merge = append(g.result.Merge, e)
+}
+
+func skipDir(d fs.DirEntry) error {
+	if d.IsDir() {
+		return fs.SkipDir
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// generateInto generates spec into dir with the given policy, with stub
+// templates and recorded commands.
+func generateInto(t *testing.T, dir string, spec Spec, policy ExistingPolicy) (*Result, error) {
+	t.Helper()
+	g := &Generator{Source: stubSource{}, Runner: &RecordingRunner{}, Dir: dir, Existing: policy}
+	return g.Generate(context.Background(), spec)
+}
+
+func TestMergeKeepsTheEarlierLock(t *testing.T) {
+	dir := t.TempDir()
+	spec := Spec{Language: "go", Name: "svc", Framework: "gin", Features: []string{"docker"}}
+	if _, err := generateInto(t, dir, spec, RefuseExisting); err != nil {
+		t.Fatal(err)
+	}
+	project := filepath.Join(dir, "svc")
+	old, err := ReadLockFile(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	editFile(t, filepath.Join(project, "main.go"), func(string) string { return "package main // mine\n" })
+
+	spec.Features = nil
+	if _, err := generateInto(t, dir, spec, MergeExisting); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := ReadLockFile(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.File("Dockerfile") == nil || len(lock.Spec.Features) != 1 {
+		t.Errorf("the docker feature was dropped from the lockfile: %v, %+v", lock.Spec.Features, lock.Files)
+	}
+	if got, want := lock.File("main.go"), old.File("main.go"); got == nil || *got != *want {
+		t.Errorf("main.go entry = %+v, want the earlier %+v", got, want)
+	}
+}
+
+func TestMergeRefusesUnreadableLockBeforeMoving(t *testing.T) {
+	tests := map[string]string{
+		"corrupt": "{",
+		"newer":   `{"version": 99}`,
+	}
+	for name, lock := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			project := filepath.Join(dir, "svc")
+			if err := os.MkdirAll(project, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(project, LockFileName), []byte(lock), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := generateInto(t, dir, Spec{Language: "go", Name: "svc", Framework: "gin"}, MergeExisting)
+			if err == nil || !strings.Contains(err.Error(), LockFileName) {
+				t.Fatalf("err = %v, want it to name %s", err, LockFileName)
+			}
+			entries, _ := os.ReadDir(project)
+			if len(entries) != 1 {
+				t.Errorf("files were moved into the project: %v", entries)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+				t.Errorf("a staging directory was left behind: %v", entries)
+			}
+		})
+	}
+}
+
+// mergeEntry returns the entry recorded for path, or a zero one.
+func mergeEntry(result *Result, path string) MergeEntry {
+	for _, e := range result.Merge {
+		if e.Path == path {
+			return e
+		}
+	}
+	return MergeEntry{}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestExistingPolicies(t *testing.T) {
+	const mine = "package main // mine\n"
+	tests := []struct {
+		policy ExistingPolicy
+		main   string
+		action string
+	}{
+		{OverwriteExisting, "// templates/go/gin/main.txt\n", MergeOverwritten},
+		{MergeExisting, mine, MergeConflict},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		project := filepath.Join(dir, "svc")
+		if err := os.MkdirAll(project, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range map[string]string{"main.go": mine, "notes.md": "todo\n"} {
+			if err := os.WriteFile(filepath.Join(project, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		spec := Spec{Language: "go", Name: "svc", Framework: "gin"}
+
+		if _, err := generateInto(t, dir, spec, RefuseExisting); !errors.As(err, new(*TargetExistsError)) {
+			t.Fatalf("refuse: err = %v, want a *TargetExistsError", err)
+		}
+		if _, err := os.Stat(filepath.Join(project, "api")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("refuse: api/ was written into the project")
+		}
+
+		result, err := generateInto(t, dir, spec, tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := readFile(t, filepath.Join(project, "main.go")); got != tt.main {
+			t.Errorf("policy %d: main.go = %q, want %q", tt.policy, got, tt.main)
+		}
+		if got := mergeEntry(result, "main.go").Action; got != tt.action {
+			t.Errorf("policy %d: main.go action = %q, want %q", tt.policy, got, tt.action)
+		}
+		if got := mergeEntry(result, "api/").Action; got != MergeCreated {
+			t.Errorf("policy %d: api/ action = %q, want %q", tt.policy, got, MergeCreated)
+		}
+		if got := readFile(t, filepath.Join(project, "notes.md")); got != "todo\n" {
+			t.Errorf("policy %d: notes.md = %q", tt.policy, got)
+		}
+		lock, err := ReadLockFile(project)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f := lock.File("main.go"); f == nil || f.Kept != (tt.policy == MergeExisting) {
+			t.Errorf("policy %d: main.go lock entry = %+v", tt.policy, f)
+		}
+	}
+}
+
+func TestMergeKeepsWhatIsInTheWay(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string // the path created as the other kind
+		dir    bool
+		reason string
+		// unlocked must be left out of the lockfile.
+		unlocked string
+	}{
+		{"directory instead of a file", "main.go", true, "a directory is in the way of the generated file", "main.go"},
+		{"file instead of a directory", "api", false, "a file is in the way of the generated directory", "api/api.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			project := filepath.Join(dir, "svc")
+			path := filepath.Join(project, tt.in)
+			if tt.dir {
+				path = filepath.Join(path, "keep.txt")
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte("mine\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := generateInto(t, dir, Spec{Language: "go", Name: "svc", Framework: "gin"}, OverwriteExisting)
+			if err != nil {
+				t.Fatal(err)
+			}
+			name := tt.in
+			if !tt.dir {
+				name += "/"
+			}
+			if e := mergeEntry(result, name); e.Action != MergeConflict || e.Reason != tt.reason {
+				t.Errorf("%s: entry = %+v, want a conflict because %s", name, e, tt.reason)
+			}
+			if got := readFile(t, path); got != "mine\n" {
+				t.Errorf("%s was replaced: %q", path, got)
+			}
+			lock, err := ReadLockFile(project)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f := lock.File(tt.unlocked); f != nil {
+				t.Errorf("%s is in the lockfile: %+v", tt.unlocked, f)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package forge
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// checkNames returns the problems with the project name, which becomes the
+// directory name, and with the module or package name derived from it,
+// which must suit the language: a Go module path, an npm package name or a
+// Python identifier.
+func checkNames(spec Spec) []string {
+	var problems []string
+	if p := checkDirName(spec.Name); p != "" {
+		problems = append(problems, p)
+	}
+
+	name, flag := spec.Module, "--module"
+	if spec.Language != "go" {
+		flag = "--package-name"
+	}
+	if name == "" {
+		if len(problems) > 0 {
+			return problems
+		}
+		name = spec.Name
+	}
+
+	var problem, suggestion string
+	switch spec.Language {
+	case "go":
+		problem = checkGoModulePath(name)
+		suggestion = kebabCase(name)
+	case "node":
+		problem = checkNPMName(name)
+		suggestion = kebabCase(name)
+	case "python":
+		problem = checkPythonIdentifier(name)
+		suggestion = snakeCase(name)
+		if suggestion != "" && unicode.IsDigit(rune(suggestion[0])) {
+			suggestion = "app_" + suggestion
+		}
+	}
+	if problem == "" {
+		return problems
+	}
+
+	what := map[string]string{"go": "Go module path", "node": "npm package name", "python": "Python package name"}[spec.Language]
+	msg := fmt.Sprintf("%q is not a valid %s: %s", name, what, problem)
+	if spec.Module == "" && suggestion != "" && suggestion != name {
+		msg += fmt.Sprintf("; pick another project name or set %s, e.g. %s %s", flag, flag, suggestion)
+	}
+	return append(problems, msg)
+}
+
+// checkDirName explains why name cannot be used as the project directory,
+// or returns "".
+func checkDirName(name string) string {
+	switch {
+	case name == "":
+		return "a project name is required"
+	case name == "." || name == ".." || strings.ContainsAny(name, `/\`):
+		return fmt.Sprintf("project name %q must not be a path; use --output-dir to generate elsewhere", name)
+	case strings.HasPrefix(name, "-") || strings.HasPrefix(name, "."):
+		return fmt.Sprintf("project name %q must not start with %q", name, name[:1])
+	case strings.ContainsFunc(name, unicode.IsSpace):
+		return fmt.Sprintf("project name %q must not contain spaces", name)
+	case strings.ContainsFunc(name, func(r rune) bool { return unicode.IsControl(r) || strings.ContainsRune(`<>:"|?*`, r) }):
+		return fmt.Sprintf("project name %q contains characters that are not allowed in file names", name)
+	}
+	return ""
+}
+
+// checkGoModulePath applies the rules of 'go mod init' to path.
+func checkGoModulePath(path string) string {
+	if strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+		return "it must not start or end with a slash"
+	}
+	for _, elem := range strings.Split(path, "/") {
+		switch {
+		case elem == "":
+			return "it must not contain empty path elements"
+		case elem == "." || elem == "..":
+			return "it must not contain . or .. elements"
+		case strings.HasPrefix(elem, ".") || strings.HasSuffix(elem, "."):
+			return fmt.Sprintf("element %q must not start or end with a dot", elem)
+		case strings.HasPrefix(elem, "-"):
+			return fmt.Sprintf("element %q must not start with a dash", elem)
+		case strings.ContainsFunc(elem, func(r rune) bool { return !isGoPathRune(r) }):
+			return fmt.Sprintf("element %q may only contain letters, digits and - . _ ~", elem)
+		}
+	}
+	return ""
+}
+
+func isGoPathRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-._~", r))
+}
+
+// npmNameRe matches an optionally scoped npm package name.
+var npmNameRe = regexp.MustCompile(`^(@[a-z0-9~-][a-z0-9._~-]*/)?[a-z0-9~-][a-z0-9._~-]*$`)
+
+// checkNPMName applies npm's rules for new package names.
+func checkNPMName(name string) string {
+	switch {
+	case len(name) > 214:
+		return "it must be at most 214 characters long"
+	case name != strings.ToLower(name):
+		return "it must be lower case"
+	case strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_"):
+		return "it must not start with . or _"
+	case !npmNameRe.MatchString(name):
+		return "it may only contain lower-case letters, digits and - . _ ~, with an optional @scope/ prefix"
+	case name == "node_modules" || name == "favicon.ico":
+		return "it is reserved"
+	}
+	return ""
+}
+
+var pythonKeywords = []string{
+	"False", "None", "True", "and", "as", "assert", "async", "await", "break", "class", "continue",
+	"def", "del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in",
+	"is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield",
+}
+
+// checkPythonIdentifier checks that name can be imported as a module.
+func checkPythonIdentifier(name string) string {
+	for i, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return "it may only contain letters, digits and underscores, and must not start with a digit"
+		}
+	}
+	if slices.Contains(pythonKeywords, name) {
+		return "it is a Python keyword"
+	}
+	return ""
+}
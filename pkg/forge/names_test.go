@@ -0,0 +1,49 @@
+package forge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckNames(t *testing.T) {
+	tests := []struct {
+		name string
+		spec Spec
+		// want holds a substring of each expected problem, in order.
+		want []string
+	}{
+		{"go", Spec{Language: "go", Name: "svc"}, nil},
+		{"go module flag", Spec{Language: "go", Name: "svc", Module: "github.com/acme/svc"}, nil},
+		{"node scoped", Spec{Language: "node", Name: "web", Module: "@acme/web"}, nil},
+		{"python", Spec{Language: "python", Name: "my_api"}, nil},
+		{"empty", Spec{Language: "go"}, []string{"a project name is required"}},
+		{"path", Spec{Language: "go", Name: "a/b"}, []string{"must not be a path"}},
+		{"dot", Spec{Language: "go", Name: ".hidden"}, []string{`must not start with "."`}},
+		{"space", Spec{Language: "node", Name: "my app"}, []string{"must not contain spaces"}},
+		{"file name characters", Spec{Language: "go", Name: "a:b"}, []string{"not allowed in file names"}},
+		{
+			"bad directory and module", Spec{Language: "go", Name: "a b", Module: "x//y"},
+			[]string{"must not contain spaces", "empty path elements"},
+		},
+		{"go module element", Spec{Language: "go", Name: "svc", Module: "acme/-svc"}, []string{`element "-svc" must not start with a dash`}},
+		{"go suggestion", Spec{Language: "go", Name: "My+App"}, []string{"may only contain letters, digits and - . _ ~; pick another project name or set --module, e.g. --module my-app"}},
+		{"npm upper case", Spec{Language: "node", Name: "MyApp"}, []string{"must be lower case; pick another project name or set --package-name, e.g. --package-name my-app"}},
+		{"npm reserved", Spec{Language: "node", Name: "node_modules"}, []string{"it is reserved"}},
+		{"python dash", Spec{Language: "python", Name: "my-api"}, []string{"not a valid Python package name: it may only contain letters, digits and underscores, and must not start with a digit; pick another project name or set --package-name, e.g. --package-name my_api"}},
+		{"python digit", Spec{Language: "python", Name: "1api"}, []string{"must not start with a digit; pick another project name or set --package-name, e.g. --package-name app_1api"}},
+		{"python keyword", Spec{Language: "python", Name: "svc", Module: "class"}, []string{"it is a Python keyword"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkNames(tt.spec)
+			if len(got) != len(tt.want) {
+				t.Fatalf("checkNames = %q, want %d problems", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("problem %d = %q, want it to mention %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
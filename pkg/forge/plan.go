@@ -0,0 +1,125 @@
+package forge
+
+import (
+	"sort"
+	"strings"
+)
+
+// plan is a manifest resolved against a set of options.
+type plan struct {
+	dirs         []string
+	files        []File
+	version      string
+	pre, post    []Command
+	afterCommit  []Command
+	nextSteps    []NextStep
+	ctx          RenderContext
+	delims       [2]string
+	replacements map[string]string
+}
+
+func (m *Manifest) plan(spec Spec) (*plan, error) {
+	p := &plan{
+		ctx:          newRenderContext(spec),
+		version:      m.Version,
+		delims:       m.delimiters(),
+		replacements: map[string]string{},
+	}
+	for placeholder, value := range m.Placeholders {
+		rendered, err := p.render(value)
+		if err != nil {
+			return nil, err
+		}
+		p.replacements[placeholder] = rendered
+	}
+
+	for _, d := range m.Directories {
+		if d.When.Matches(spec) {
+			path, err := p.render(d.Path)
+			if err != nil {
+				return nil, err
+			}
+			p.dirs = append(p.dirs, path)
+		}
+	}
+	for _, f := range m.Files {
+		if f.When.Matches(spec) {
+			dest, err := p.render(f.Dest)
+			if err != nil {
+				return nil, err
+			}
+			f.Dest = dest
+			p.files = append(p.files, f)
+		}
+	}
+	for _, c := range m.PreCommands {
+		if c.When.Matches(spec) {
+			p.pre = append(p.pre, c)
+		}
+	}
+	for _, c := range m.PostCommands {
+		if !c.When.Matches(spec) {
+			continue
+		}
+		if c.AfterCommit {
+			p.afterCommit = append(p.afterCommit, c)
+		} else {
+			p.post = append(p.post, c)
+		}
+	}
+	for _, s := range m.NextSteps {
+		if s.When.Matches(spec) {
+			p.nextSteps = append(p.nextSteps, s)
+		}
+	}
+	return p, nil
+}
+
+// render renders a manifest string such as a path or command argument.
+func (p *plan) render(s string) (string, error) {
+	if !strings.Contains(s, p.delims[0]) {
+		return s, nil
+	}
+	out, err := renderTemplate(s, s, p.delims, p.ctx)
+	return string(out), err
+}
+
+// renderArgs renders every argument of a command.
+func (p *plan) renderArgs(c Command) ([]string, error) {
+	args := make([]string, len(c.Run))
+	for i, arg := range c.Run {
+		rendered, err := p.render(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = rendered
+	}
+	return args, nil
+}
+
+// renderEnv renders the environment of a command as sorted KEY=VALUE pairs.
+func (p *plan) renderEnv(c Command) ([]string, error) {
+	var env []string
+	for key, value := range c.Env {
+		rendered, err := p.render(value)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, key+"="+rendered)
+	}
+	sort.Strings(env)
+	return env, nil
+}
+
+// renderFile turns a raw template into file contents. .tmpl templates are
+// executed with text/template; legacy templates get their placeholders replaced.
+func (p *plan) renderFile(key string, data []byte) ([]byte, error) {
+	if strings.HasSuffix(key, ".tmpl") {
+		return renderTemplate(key, string(data), p.delims, p.ctx)
+	}
+	content := string(data)
+	for placeholder, value := range p.replacements {
+		content = strings.ReplaceAll(content, placeholder, value)
+	}
+	return []byte(content), nil
+}
@@ -0,0 +1,284 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Registry answers which languages, frameworks, databases, ORMs and
+// features can be combined. It is built from the catalog and the framework
+// manifests, which are loaded on first use, so generation, validation and
+// listings always agree with the templates.
+type Registry struct {
+	src     TemplateSource
+	catalog *Catalog
+
+	mu        sync.Mutex
+	manifests map[string]*Manifest
+}
+
+// LoadRegistry reads the catalog from src.
+func LoadRegistry(ctx context.Context, src TemplateSource) (*Registry, error) {
+	catalog, err := LoadCatalog(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{src: src, catalog: catalog, manifests: map[string]*Manifest{}}, nil
+}
+
+// Catalog returns the languages and frameworks of the registry.
+func (r *Registry) Catalog() *Catalog {
+	return r.catalog
+}
+
+// Manifest returns the manifest of a framework listed in the catalog.
+func (r *Registry) Manifest(ctx context.Context, language, framework string) (*Manifest, error) {
+	if !r.catalog.HasFramework(language, framework) {
+		return nil, fmt.Errorf("unsupported %s framework %q", language, framework)
+	}
+	key := language + "/" + framework
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if m, ok := r.manifests[key]; ok {
+		return m, nil
+	}
+	m, err := LoadManifest(ctx, r.src, language, framework)
+	if err != nil {
+		return nil, err
+	}
+	r.manifests[key] = m
+	return m, nil
+}
+
+// Manifests returns the manifest of every framework, optionally restricted
+// to one language, in catalog order.
+func (r *Registry) Manifests(ctx context.Context, language string) ([]*Manifest, error) {
+	var manifests []*Manifest
+	for _, l := range r.catalog.Languages {
+		if language != "" && l.Name != language {
+			continue
+		}
+		for _, fw := range l.Frameworks {
+			m, err := r.Manifest(ctx, l.Name, fw)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, m)
+		}
+	}
+	return manifests, nil
+}
+
+// DatabaseOptions returns the databases that can complete spec given its
+// ORM, if any, and TypeScript setting. An empty string means the database
+// may be left out.
+func (r *Registry) DatabaseOptions(ctx context.Context, spec Spec) ([]string, error) {
+	m, err := r.Manifest(ctx, spec.Language, spec.Framework)
+	if err != nil {
+		return nil, err
+	}
+	var dbs []string
+	for _, c := range m.Combinations {
+		if (spec.ORM != "" && c.ORM != spec.ORM) || !c.allowsTypeScript(spec.TypeScript) {
+			continue
+		}
+		if c.allowsDatabase("") && !slices.Contains(dbs, "") {
+			dbs = append(dbs, "")
+		}
+		for _, db := range c.Databases {
+			if !slices.Contains(dbs, db) {
+				dbs = append(dbs, db)
+			}
+		}
+	}
+	return dbs, nil
+}
+
+// ORMOptions returns the ORMs that can complete spec given its database,
+// if any, and TypeScript setting. An empty string means the ORM may be
+// left out.
+func (r *Registry) ORMOptions(ctx context.Context, spec Spec) ([]string, error) {
+	m, err := r.Manifest(ctx, spec.Language, spec.Framework)
+	if err != nil {
+		return nil, err
+	}
+	var orms []string
+	for _, c := range m.Combinations {
+		if c.allowsTypeScript(spec.TypeScript) && c.allowsDatabase(spec.Database) && !slices.Contains(orms, c.ORM) {
+			orms = append(orms, c.ORM)
+		}
+	}
+	return orms, nil
+}
+
+// Check returns the problems with the language, framework, database, ORM,
+// TypeScript and feature choices of spec, each suggesting valid
+// alternatives. It returns nil when the combination is supported.
+func (r *Registry) Check(ctx context.Context, spec Spec) []string {
+	lang := r.catalog.Language(spec.Language)
+	if lang == nil {
+		var names []string
+		for _, l := range r.catalog.Languages {
+			names = append(names, l.Name)
+		}
+		return []string{unsupported("language", spec.Language, names)}
+	}
+	if !slices.Contains(lang.Frameworks, spec.Framework) {
+		for _, other := range r.catalog.Languages {
+			if slices.Contains(other.Frameworks, spec.Framework) {
+				return []string{fmt.Sprintf("%s is a %s framework, not a %s one; %s frameworks are %s",
+					spec.Framework, other.Name, spec.Language, spec.Language, strings.Join(lang.Frameworks, ", "))}
+			}
+		}
+		return []string{unsupported(spec.Language+" framework", spec.Framework, lang.Frameworks)}
+	}
+
+	m, err := r.Manifest(ctx, spec.Language, spec.Framework)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	if spec.TypeScript && spec.Language != "node" {
+		problems = append(problems, "typescript is only available for node")
+	}
+	if p := m.checkCombination(spec); p != "" {
+		problems = append(problems, p)
+	}
+	for _, f := range spec.Features {
+		if !m.HasFeature(f) {
+			var names []string
+			for _, feature := range m.Features {
+				names = append(names, feature.Name)
+			}
+			problems = append(problems, unsupported(spec.Framework+" feature", f, names))
+		}
+	}
+	return problems
+}
+
+// checkCombination explains why the ORM, database and TypeScript choices
+// of spec do not form a supported combination, or returns "".
+func (m *Manifest) checkCombination(spec Spec) string {
+	var forORM []Combination
+	for _, c := range m.Combinations {
+		if c.ORM == spec.ORM {
+			forORM = append(forORM, c)
+		}
+	}
+	if len(forORM) == 0 {
+		if spec.ORM == "" {
+			return fmt.Sprintf("%s requires an ORM: %s", m.Framework, strings.Join(m.ORMs(), ", "))
+		}
+		return unsupported(m.Framework+" ORM", spec.ORM, m.ORMs())
+	}
+
+	var dbs []string
+	for _, c := range forORM {
+		if !c.allowsTypeScript(spec.TypeScript) {
+			continue
+		}
+		if c.allowsDatabase(spec.Database) {
+			return ""
+		}
+		dbs = append(dbs, c.Databases...)
+	}
+
+	subject := m.Framework + " with " + spec.ORM
+	if spec.ORM == "" {
+		subject = m.Framework + " without an ORM"
+	}
+	switch {
+	case len(dbs) == 0 && spec.TypeScript:
+		return fmt.Sprintf("%s is only available for JavaScript projects", subject)
+	case len(dbs) == 0 && forORM[0].TypeScript != nil && *forORM[0].TypeScript:
+		return fmt.Sprintf("%s requires --typescript", subject)
+	case spec.Database == "":
+		return fmt.Sprintf("%s requires a database: %s", subject, strings.Join(dbs, ", "))
+	}
+
+	msg := fmt.Sprintf("%s does not support database %q", subject, spec.Database)
+	if s := closest(spec.Database, dbs); s != "" {
+		msg += fmt.Sprintf("; did you mean %q? Supported: %s", s, strings.Join(dbs, ", "))
+	} else if len(dbs) > 0 {
+		msg += "; supported: " + strings.Join(dbs, ", ")
+	}
+	var orms []string
+	for _, c := range m.Combinations {
+		if c.ORM != "" && slices.Contains(c.Databases, spec.Database) && c.allowsTypeScript(spec.TypeScript) && !slices.Contains(orms, c.ORM) {
+			orms = append(orms, c.ORM)
+		}
+	}
+	if len(orms) > 0 {
+		msg += fmt.Sprintf("; %s is available with --orm %s", spec.Database, strings.Join(orms, " or --orm "))
+	}
+	return msg
+}
+
+func (c Combination) allowsDatabase(db string) bool {
+	if db == "" {
+		return len(c.Databases) == 0 || c.DefaultDatabase != ""
+	}
+	return slices.Contains(c.Databases, db)
+}
+
+func (c Combination) allowsTypeScript(ts bool) bool {
+	return c.TypeScript == nil || *c.TypeScript == ts
+}
+
+// unsupported describes an unknown value, suggesting the closest valid one.
+func unsupported(what, value string, valid []string) string {
+	msg := fmt.Sprintf("unsupported %s %q", what, value)
+	if len(valid) == 0 {
+		return msg + "; none are available"
+	}
+	if s := closest(value, valid); s != "" {
+		return msg + fmt.Sprintf("; did you mean %q? Available: %s", s, strings.Join(valid, ", "))
+	}
+	return msg + "; available: " + strings.Join(valid, ", ")
+}
+
+// closest returns the option nearest to s by edit distance, if it is close
+// enough to be a likely typo.
+func closest(s string, options []string) string {
+	best, bestDist := "", -1
+	for _, o := range options {
+		d := editDistance(strings.ToLower(s), strings.ToLower(o))
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = o, d
+		}
+	}
+	if bestDist < 0 || bestDist > max(1, len(s)/3) {
+		return ""
+	}
+	return best
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// the Levenshtein distance with adjacent transpositions counted as one edit.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
@@ -0,0 +1,48 @@
+package forge
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRegistryCheck(t *testing.T) {
+	ctx := context.Background()
+	r, err := LoadRegistry(ctx, NewEmbedSource())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		spec Spec
+		want []string
+	}{
+		{"gin", Spec{Language: "go", Framework: "gin"}, nil},
+		{"gin gorm default database", Spec{Language: "go", Framework: "gin", ORM: "gorm"}, nil},
+		{"gin gorm postgres docker", Spec{Language: "go", Framework: "gin", ORM: "gorm", Database: "postgres", Features: []string{"docker"}}, nil},
+		{"express drizzle", Spec{Language: "node", Framework: "express", ORM: "drizzle", Database: "postgres", TypeScript: true}, nil},
+		{"unknown language", Spec{Language: "rust", Framework: "axum"}, []string{`unsupported language "rust"`}},
+		{"framework typo", Spec{Language: "go", Framework: "gni"}, []string{`did you mean "gin"?`}},
+		{"other language's framework", Spec{Language: "go", Framework: "express"}, []string{"express is a node framework, not a go one"}},
+		{"unknown ORM", Spec{Language: "go", Framework: "gin", ORM: "gorn"}, []string{`unsupported gin ORM "gorn"; did you mean "gorm"?`}},
+		{"database without ORM", Spec{Language: "go", Framework: "gin", Database: "postgres"}, []string{`gin without an ORM does not support database "postgres"; postgres is available with --orm gorm`}},
+		{"database typo", Spec{Language: "go", Framework: "gin", ORM: "gorm", Database: "postgress"}, []string{`did you mean "postgres"?`}},
+		{"drizzle needs typescript", Spec{Language: "node", Framework: "express", ORM: "drizzle", Database: "postgres"}, []string{"express with drizzle requires --typescript"}},
+		{"drizzle needs a database", Spec{Language: "node", Framework: "express", ORM: "drizzle", TypeScript: true}, []string{"requires a database: postgres, mysql"}},
+		{"typescript outside node", Spec{Language: "go", Framework: "gin", TypeScript: true}, []string{"typescript is only available for node"}},
+		{"unknown feature", Spec{Language: "go", Framework: "gin", Features: []string{"dockr"}}, []string{`unsupported gin feature "dockr"; did you mean "docker"?`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Check(ctx, tt.spec)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Check = %q, want %d problems", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("problem %d = %q, want it to mention %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package forge
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// DefaultDelimiters are used for .tmpl templates unless a manifest sets its
+// own. They do not clash with JSX, Jinja or Go templates in the output.
+var DefaultDelimiters = [2]string{"[[", "]]"}
+
+// RenderContext is the data .tmpl templates, destination paths, commands
+// and placeholder values are rendered with.
+type RenderContext struct {
+	ProjectName string
+	// ModulePath is the Go module path, npm package name or Python package
+	// name: Spec.Module, or else the project name.
+	ModulePath string
+	Language   string
+	Framework  string
+	Database   string
+	ORM        string
+	TypeScript bool
+	// Features holds the optional features that were enabled, e.g. .Features.docker.
+	Features map[string]bool
+}
+
+func newRenderContext(spec Spec) RenderContext {
+	ctx := RenderContext{
+		ProjectName: spec.Name,
+		ModulePath:  spec.Name,
+		Language:    spec.Language,
+		Framework:   spec.Framework,
+		Database:    spec.Database,
+		ORM:         spec.ORM,
+		TypeScript:  spec.TypeScript,
+		Features:    map[string]bool{},
+	}
+	if spec.Module != "" {
+		ctx.ModulePath = spec.Module
+	}
+	for _, f := range spec.Features {
+		ctx.Features[f] = true
+	}
+	return ctx
+}
+
+// FeatureList returns the enabled features in sorted order.
+func (c RenderContext) FeatureList() []string {
+	list := make([]string, 0, len(c.Features))
+	for f, on := range c.Features {
+		if on {
+			list = append(list, f)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+var templateFuncs = template.FuncMap{
+	"snake":  snakeCase,
+	"kebab":  kebabCase,
+	"camel":  camelCase,
+	"pascal": pascalCase,
+	"upper":  strings.ToUpper,
+	"lower":  strings.ToLower,
+	"title":  pascalCase,
+	"join":   strings.Join,
+	"plural": plural,
+}
+
+// renderTemplate executes a text/template with the given delimiters.
+func renderTemplate(name, text string, delims [2]string, data any) ([]byte, error) {
+	t, err := template.New(name).Delims(delims[0], delims[1]).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering template %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// words splits an identifier such as "myApp-name_v2" into lower-case words.
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	runes := []rune(s)
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return out
+}
+
+func snakeCase(s string) string {
+	return strings.Join(words(s), "_")
+}
+
+func kebabCase(s string) string {
+	return strings.Join(words(s), "-")
+}
+
+func pascalCase(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		r := []rune(w)
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+	return b.String()
+}
+
+func camelCase(s string) string {
+	p := []rune(pascalCase(s))
+	if len(p) == 0 {
+		return ""
+	}
+	return strings.ToLower(string(p[0])) + string(p[1:])
+}
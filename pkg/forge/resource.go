@@ -0,0 +1,739 @@
+package forge
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// FieldTypes are the types a resource field can have.
+var FieldTypes = []string{"string", "text", "int", "float", "bool", "datetime"}
+
+// Field is a field of a resource, such as price:float. Its methods give
+// the matching type in each language the templates generate.
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// pick returns the entry of types at the index of the field's type in
+// FieldTypes. Fields that did not come from ParseFields may have any type,
+// so an unknown one is an error rather than a panic.
+func (f Field) pick(types ...string) (string, error) {
+	i := slices.Index(FieldTypes, f.Type)
+	if i < 0 {
+		return "", fmt.Errorf("%s: %s", f.Name, unsupported("field type", f.Type, FieldTypes))
+	}
+	return types[i], nil
+}
+
+// GoType returns the Go type of the field.
+func (f Field) GoType() (string, error) {
+	return f.pick("string", "string", "int", "float64", "bool", "time.Time")
+}
+
+// TSType returns the TypeScript type of the field.
+func (f Field) TSType() (string, error) {
+	return f.pick("string", "string", "number", "number", "boolean", "Date")
+}
+
+// PyType returns the Python type of the field.
+func (f Field) PyType() (string, error) {
+	return f.pick("str", "str", "int", "float", "bool", "datetime")
+}
+
+// SQLAlchemyType returns the SQLAlchemy column type of the field.
+func (f Field) SQLAlchemyType() (string, error) {
+	return f.pick("String(255)", "Text", "Integer", "Float", "Boolean", "DateTime")
+}
+
+// MongooseType returns the Mongoose schema type of the field.
+func (f Field) MongooseType() (string, error) {
+	return f.pick("String", "String", "Number", "Number", "Boolean", "Date")
+}
+
+// SchemaType returns the type checked by the generated validators:
+// string, integer, number, boolean or datetime.
+func (f Field) SchemaType() (string, error) {
+	return f.pick("string", "string", "integer", "number", "boolean", "datetime")
+}
+
+// Example returns a valid JSON value for the field, for generated tests.
+func (f Field) Example() (string, error) {
+	return f.pick(`"example `+f.Name+`"`, `"example `+f.Name+`"`, "42", "9.99", "true", `"2024-01-02T15:04:05Z"`)
+}
+
+// PyExample returns Example as a Python literal.
+func (f Field) PyExample() (string, error) {
+	example, err := f.Example()
+	return strings.ReplaceAll(example, "true", "True"), err
+}
+
+// Invalid returns a JSON value of the wrong type for the field.
+func (f Field) Invalid() (string, error) {
+	return f.pick("42", "42", `"many"`, `"cheap"`, `"yes"`, `"someday"`)
+}
+
+// Resource is a CRUD resource added to a project with AddResource.
+type Resource struct {
+	// Name is the singular name, e.g. "product" or "order-item".
+	Name   string  `json:"name"`
+	Fields []Field `json:"fields"`
+	// Path is where the routes are mounted; defaults to /<plural name>,
+	// e.g. /order-items.
+	Path string `json:"path"`
+}
+
+// Label returns the name for use in prose, e.g. "order item".
+func (r Resource) Label() string {
+	return strings.ReplaceAll(r.Name, "_", " ")
+}
+
+// Uses reports whether some field has the given type, e.g. to import time.
+func (r Resource) Uses(typ string) bool {
+	return slices.ContainsFunc(r.Fields, func(f Field) bool { return f.Type == typ })
+}
+
+var fieldNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// reservedFields are generated for every resource.
+var reservedFields = []string{"id", "created_at", "updated_at"}
+
+// ParseFields parses a field list such as "name:string,price:float".
+func ParseFields(s string) ([]Field, error) {
+	var fields []Field
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, typ, ok := strings.Cut(item, ":")
+		if !ok {
+			typ = "string"
+		}
+		f := Field{Name: strings.TrimSpace(name), Type: strings.ToLower(strings.TrimSpace(typ))}
+		if err := checkField(f, fields); err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("a resource needs at least one field")
+	}
+	return fields, nil
+}
+
+// checkField validates a field's name and type, given the fields before it.
+func checkField(f Field, before []Field) error {
+	switch {
+	case !fieldNameRe.MatchString(f.Name):
+		return fmt.Errorf("invalid field name %q: use lower-case letters, digits and underscores", f.Name)
+	case slices.Contains(reservedFields, f.Name):
+		return fmt.Errorf("field %q is generated for every resource", f.Name)
+	case slices.Contains(pythonKeywords, f.Name):
+		return fmt.Errorf("field %q is a Python keyword", f.Name)
+	case !slices.Contains(FieldTypes, f.Type):
+		return fmt.Errorf("%s: %s", f.Name, unsupported("field type", f.Type, FieldTypes))
+	case slices.ContainsFunc(before, func(o Field) bool { return o.Name == f.Name }):
+		return fmt.Errorf("field %q is given twice", f.Name)
+	}
+	return nil
+}
+
+// checkResource validates the name, fields and path of a resource, which
+// need not come from ParseFields, e.g. when read from JSON.
+func checkResource(r Resource) error {
+	name := snakeCase(r.Name)
+	switch {
+	case name == "" || !fieldNameRe.MatchString(name):
+		return fmt.Errorf("invalid resource name %q: use a singular noun such as product or order-item", r.Name)
+	case slices.Contains(pythonKeywords, name):
+		return fmt.Errorf("resource name %q cannot be used; pick another", r.Name)
+	case len(r.Fields) == 0:
+		return errors.New("a resource needs at least one field")
+	case r.Path != "" && (!strings.HasPrefix(r.Path, "/") || strings.HasSuffix(r.Path, "/") || strings.ContainsAny(r.Path, " \t?#:{}")):
+		return fmt.Errorf("invalid route path %q: use an absolute path without a trailing slash or parameters, e.g. /api/products", r.Path)
+	}
+	for i, f := range r.Fields {
+		if err := checkField(f, r.Fields[:i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plural returns the English plural of a lower-case noun, well enough for
+// table and route names.
+func plural(s string) string {
+	switch {
+	case strings.HasSuffix(s, "s") || strings.HasSuffix(s, "x") || strings.HasSuffix(s, "z") ||
+		strings.HasSuffix(s, "ch") || strings.HasSuffix(s, "sh"):
+		return s + "es"
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsRune("aeiou", rune(s[len(s)-2])):
+		return s[:len(s)-1] + "ies"
+	}
+	return s + "s"
+}
+
+// ResourceContext is the data resource templates and edits are rendered with.
+type ResourceContext struct {
+	RenderContext
+	Resource Resource
+	// Vars holds what an edit's vars matched in the edited file.
+	Vars map[string]string
+}
+
+// Statuses of the edits in an AddResourceResult.
+const (
+	EditApplied = "applied"
+	EditPresent = "present"
+	EditManual  = "manual"
+)
+
+// AddResourceOptions control Generator.AddResource.
+type AddResourceOptions struct {
+	// Force overwrites resource files that already exist.
+	Force bool
+	// DryRun computes the result without writing anything.
+	DryRun bool
+}
+
+// AddResourceResult summarises AddResource.
+type AddResourceResult struct {
+	ProjectPath string         `json:"project_path"`
+	Resource    string         `json:"resource"`
+	Spec        Spec           `json:"spec"`
+	Files       []string       `json:"files"`
+	Edits       []ResourceEdit `json:"edits"`
+	NextSteps   []RenderedStep `json:"next_steps,omitempty"`
+	Fields      []Field        `json:"fields"`
+	contents    map[string][]byte
+	locked      []LockedFile
+}
+
+// ResourceEdit reports an edit of an existing file: applied, already
+// present, or manual when the place to insert it was not found, in which
+// case Snippet is the text to add by hand.
+type ResourceEdit struct {
+	File    string `json:"file"`
+	Status  string `json:"status"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// RenderedStep is a NextStep with its commands rendered.
+type RenderedStep struct {
+	Description string   `json:"description"`
+	Commands    []string `json:"commands,omitempty"`
+}
+
+// AddResource generates the model, controller, routes, validation and
+// tests of a CRUD resource in the project in dir, and wires the routes into
+// its router. The language, framework and ORM come from the project's
+// lockfile or, without one, from its dependency files.
+func (g *Generator) AddResource(ctx context.Context, dir string, res Resource, opts AddResourceOptions) (*AddResourceResult, error) {
+	if err := checkResource(res); err != nil {
+		return nil, err
+	}
+	res.Name = snakeCase(res.Name)
+	if res.Path == "" {
+		res.Path = "/" + kebabCase(plural(res.Name))
+	}
+	spec, err := DetectSpec(dir)
+	if err != nil {
+		return nil, err
+	}
+	pack, delims, err := g.resourcePack(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	data := ResourceContext{RenderContext: newRenderContext(spec), Resource: res, Vars: map[string]string{}}
+	render := func(s string) (string, error) {
+		out, err := renderTemplate(s, s, delims, data)
+		return string(out), err
+	}
+	result := &AddResourceResult{ProjectPath: dir, Resource: res.Name, Spec: spec, Fields: res.Fields,
+		Files: []string{}, Edits: []ResourceEdit{}, contents: map[string][]byte{}}
+
+	var existing []string
+	for _, f := range pack.Files {
+		if !f.When.Matches(spec) {
+			continue
+		}
+		dest, err := render(f.Dest)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := fetchTemplate(ctx, g.source(), f.Template)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Template, err)
+		}
+		content, err := renderTemplate(f.Template, string(raw), delims, data)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(dest, ".go") {
+			if content, err = format.Source(content); err != nil {
+				return nil, fmt.Errorf("formatting %s: %w", dest, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(dest))); err == nil {
+			existing = append(existing, dest)
+		}
+		result.Files = append(result.Files, dest)
+		result.contents[dest] = content
+		result.locked = append(result.locked, LockedFile{Path: dest, Template: f.Template, TemplateHash: HashContent(raw), Hash: HashContent(content)})
+	}
+	if len(existing) > 0 && !opts.Force {
+		verb := "exists"
+		if len(existing) > 1 {
+			verb = "exist"
+		}
+		return nil, fmt.Errorf("%s already %s; pass --force to overwrite", strings.Join(existing, ", "), verb)
+	}
+
+	edited := map[string]string{}
+	for _, e := range pack.Edits {
+		if !e.When.Matches(spec) {
+			continue
+		}
+		edit, content, err := applyEdit(dir, e, data, delims, edited)
+		if err != nil {
+			return nil, err
+		}
+		if edit.Status == EditApplied {
+			edited[edit.File] = content
+		}
+		result.Edits = append(result.Edits, edit)
+	}
+	settleEdits(result.Edits, edited)
+
+	for _, s := range pack.NextSteps {
+		if !s.When.Matches(spec) {
+			continue
+		}
+		description, err := render(s.Description)
+		if err != nil {
+			return nil, err
+		}
+		step := RenderedStep{Description: description}
+		for _, c := range s.Commands {
+			rendered, err := render(c)
+			if err != nil {
+				return nil, err
+			}
+			step.Commands = append(step.Commands, rendered)
+		}
+		result.NextSteps = append(result.NextSteps, step)
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	for _, p := range result.Files {
+		dest := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return result, err
+		}
+		if err := os.WriteFile(dest, result.contents[p], 0644); err != nil {
+			return result, err
+		}
+	}
+	for p, content := range edited {
+		dest := filepath.Join(dir, filepath.FromSlash(p))
+		info, err := os.Stat(dest)
+		if err != nil {
+			return result, err
+		}
+		if err := os.WriteFile(dest, []byte(content), info.Mode().Perm()); err != nil {
+			return result, err
+		}
+	}
+	return result, recordResource(dir, res, result, edited)
+}
+
+// recordResource adds the resource to the lockfile of the project in dir,
+// if it has one, replacing an earlier one of the same name, so Diff and
+// Upgrade account for its files and edits.
+func recordResource(dir string, res Resource, result *AddResourceResult, edited map[string]string) error {
+	lock, err := ReadLockFile(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	locked := LockedResource{Resource: res, Files: result.locked}
+	sort.Slice(locked.Files, func(i, j int) bool { return locked.Files[i].Path < locked.Files[j].Path })
+	for p, content := range edited {
+		locked.Edited = append(locked.Edited, GeneratedFile{Path: p, Hash: HashContent([]byte(content))})
+	}
+	sort.Slice(locked.Edited, func(i, j int) bool { return locked.Edited[i].Path < locked.Edited[j].Path })
+	lock.Resources = slices.DeleteFunc(lock.Resources, func(r LockedResource) bool { return r.Name == res.Name })
+	lock.Resources = append(lock.Resources, locked)
+
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, LockFileName), append(data, '\n'))
+}
+
+// Content returns what AddResource generated for path, which is useful
+// with DryRun.
+func (r *AddResourceResult) Content(path string) []byte {
+	return r.contents[path]
+}
+
+// resourcePack returns the resource pack of the spec's framework. Packs
+// published before resources were supported fall back to the embedded one.
+func (g *Generator) resourcePack(ctx context.Context, spec Spec) (*ResourcePack, [2]string, error) {
+	m, err := LoadManifest(ctx, g.source(), spec.Language, spec.Framework)
+	if err != nil {
+		return nil, DefaultDelimiters, err
+	}
+	if m.Resource == nil {
+		if embedded, err := LoadManifest(ctx, NewEmbedSource(), spec.Language, spec.Framework); err == nil {
+			m = embedded
+		}
+	}
+	if m.Resource == nil {
+		return nil, DefaultDelimiters, fmt.Errorf("the %s templates cannot generate resources", spec.Framework)
+	}
+	return m.Resource, m.delimiters(), nil
+}
+
+// settleEdits makes the edits of each file all-or-nothing: when one of them
+// is manual, or the edited Go file no longer formats, the file is left
+// alone and its applied edits become manual too. Edited Go files are
+// formatted.
+func settleEdits(edits []ResourceEdit, edited map[string]string) {
+	for file, content := range edited {
+		ok := !slices.ContainsFunc(edits, func(e ResourceEdit) bool { return e.File == file && e.Status == EditManual })
+		if ok && strings.HasSuffix(file, ".go") {
+			formatted, err := format.Source([]byte(content))
+			ok = err == nil
+			edited[file] = string(formatted)
+		}
+		for i := range edits {
+			if edits[i].File != file || edits[i].Status != EditApplied {
+				continue
+			}
+			if ok {
+				edits[i].Snippet = ""
+			} else {
+				edits[i].Status = EditManual
+			}
+		}
+		if !ok {
+			delete(edited, file)
+		}
+	}
+}
+
+// applyEdit renders an edit and inserts it into its file, whose content may
+// already have been edited. Edits that cannot be placed are returned as
+// manual. The snippet to add is returned either way; settleEdits clears it
+// once the file's edits are known to apply.
+func applyEdit(dir string, e Edit, data ResourceContext, delims [2]string, edited map[string]string) (ResourceEdit, string, error) {
+	file, err := renderTemplate(e.File, e.File, delims, data)
+	if err != nil {
+		return ResourceEdit{}, "", err
+	}
+	edit := ResourceEdit{File: string(file), Status: EditManual}
+	content, ok := edited[edit.File]
+	if !ok {
+		raw, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(edit.File)))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return edit, "", err
+		}
+		content = string(raw)
+	}
+
+	found := true
+	data.Vars = map[string]string{}
+	for name, expr := range e.Vars {
+		re, err := regexp.Compile("(?m)" + expr)
+		if err != nil {
+			return edit, "", fmt.Errorf("invalid var %s in resource edit of %s: %w", name, edit.File, err)
+		}
+		data.Vars[name] = "<" + name + ">"
+		if value := firstSubmatch(re, content); value != "" {
+			data.Vars[name] = value
+		} else {
+			found = false
+		}
+	}
+	insert, err := renderTemplate(e.Insert, e.Insert, delims, data)
+	if err != nil {
+		return edit, "", err
+	}
+	edit.Snippet = strings.TrimRight(string(insert), "\n")
+	if content != "" && containsLines(content, edit.Snippet) {
+		edit.Status, edit.Snippet = EditPresent, ""
+		return edit, content, nil
+	}
+	if !found {
+		return edit, content, nil
+	}
+
+	// Insert after the first run of lines matching an After anchor, else
+	// before the first line matching a Before anchor, at the indentation of
+	// that line.
+	lines := strings.SplitAfter(content, "\n")
+	inFunc := func(int) bool { return true }
+	if e.InFunc && strings.HasSuffix(edit.File, ".go") {
+		inFunc = goFuncLines(lines)
+	}
+	at, indent := -1, ""
+	for k, anchors := range [][]string{e.After, e.Before} {
+		for _, expr := range anchors {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return edit, "", fmt.Errorf("invalid anchor in resource edit of %s: %w", edit.File, err)
+			}
+			match := func(i int) bool { return inFunc(i) && re.MatchString(strings.TrimRight(lines[i], "\r\n")) }
+			i := 0
+			for i < len(lines) && !match(i) {
+				i++
+			}
+			if i == len(lines) {
+				continue
+			}
+			if k == 0 {
+				for i+1 < len(lines) && match(i+1) {
+					i++
+				}
+				at = i + 1
+			} else {
+				at = i
+			}
+			indent = lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+			break
+		}
+		if at >= 0 {
+			break
+		}
+	}
+	if at < 0 {
+		return edit, content, nil
+	}
+	if at > 0 && !strings.HasSuffix(lines[at-1], "\n") {
+		lines[at-1] += "\n"
+	}
+
+	var block strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(edit.Snippet))
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			block.WriteString(indent + line)
+		}
+		block.WriteString("\n")
+	}
+	lines = slices.Insert(lines, at, block.String())
+	edit.Status = EditApplied
+	return edit, strings.Join(lines, ""), nil
+}
+
+// goFuncLines reports, for each of the lines of a Go file, whether it
+// starts inside a function body, the closing brace included. The file is
+// only tokenized, so it need not compile.
+func goFuncLines(lines []string) func(int) bool {
+	src := []byte(strings.Join(lines, ""))
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, 0)
+
+	// Braces and parentheses are counted to find the body of each
+	// top-level func, skipping the struct and interface types of its
+	// signature.
+	var bodies [][2]int
+	braces, parens, start, inSignature := 0, 0, -1, false
+	for {
+		pos, tok, _ := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		switch tok {
+		case token.FUNC:
+			inSignature = inSignature || braces == 0
+		case token.LPAREN, token.LBRACK:
+			parens++
+		case token.RPAREN, token.RBRACK:
+			parens--
+		case token.SEMICOLON:
+			if braces == 0 && parens == 0 {
+				inSignature = false
+			}
+		case token.LBRACE:
+			if inSignature && braces == 0 && parens == 0 {
+				start, inSignature = file.Offset(pos), false
+			}
+			braces++
+		case token.RBRACE:
+			braces--
+			if braces == 0 && start >= 0 {
+				bodies = append(bodies, [2]int{start, file.Offset(pos)})
+				start = -1
+			}
+		}
+	}
+
+	inside := make([]bool, len(lines))
+	offset := 0
+	for i, l := range lines {
+		inside[i] = slices.ContainsFunc(bodies, func(b [2]int) bool { return b[0] < offset && offset <= b[1] })
+		offset += len(l)
+	}
+	return func(i int) bool { return inside[i] }
+}
+
+// firstSubmatch returns the first non-empty group of re's first match in s.
+func firstSubmatch(re *regexp.Regexp, s string) string {
+	for _, m := range re.FindAllStringSubmatch(s, -1) {
+		for _, g := range m[1:] {
+			if g != "" {
+				return g
+			}
+		}
+	}
+	return ""
+}
+
+// containsLines reports whether every non-blank line of snippet appears
+// in content, ignoring indentation.
+func containsLines(content, snippet string) bool {
+	for _, l := range strings.Split(snippet, "\n") {
+		if l = strings.TrimSpace(l); l != "" && !strings.Contains(content, l) {
+			return false
+		}
+	}
+	return true
+}
+
+// DetectSpec works out how the project in dir was generated: from its
+// lockfile or, for projects without one, from go.mod, package.json or
+// requirements.txt.
+func DetectSpec(dir string) (Spec, error) {
+	var spec Spec
+	lock, err := ReadLockFile(dir)
+	switch {
+	case err == nil:
+		spec = lock.Spec
+	case !errors.Is(err, fs.ErrNotExist):
+		return spec, err
+	default:
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return spec, err
+		}
+		spec.Name = filepath.Base(abs)
+		if spec, err = detectFromFiles(dir, spec); err != nil {
+			return spec, err
+		}
+	}
+	if spec.Language == "go" {
+		// go.mod is written by go mod init, so it has the real module path.
+		if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			if m := goModuleRe.FindSubmatch(data); m != nil {
+				spec.Module = string(m[1])
+			}
+		}
+	}
+	return spec, nil
+}
+
+var goModuleRe = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+
+// detectFromFiles fills in spec from the dependencies the project declares.
+func detectFromFiles(dir string, spec Spec) (Spec, error) {
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		return string(data)
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	pick := func(deps func(string) bool, options [][2]string, fallback string) string {
+		for _, o := range options {
+			if deps(o[0]) {
+				return o[1]
+			}
+		}
+		return fallback
+	}
+
+	switch {
+	case exists("go.mod"):
+		gomod := read("go.mod")
+		has := func(dep string) bool { return strings.Contains(gomod, dep) }
+		spec.Language = "go"
+		spec.Framework = pick(has, [][2]string{
+			{"github.com/gin-gonic/gin", "gin"}, {"github.com/labstack/echo", "echo"},
+			{"github.com/gofiber/fiber", "fiber"}, {"github.com/gorilla/mux", "mux"},
+		}, "http")
+		if has("gorm.io/gorm") {
+			spec.ORM = "gorm"
+			spec.Database = pick(has, [][2]string{{"gorm.io/driver/postgres", "postgres"}, {"gorm.io/driver/mysql", "mysql"}}, "sqlite")
+		}
+	case exists("package.json"):
+		var pkg struct {
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+		}
+		if err := json.Unmarshal([]byte(read("package.json")), &pkg); err != nil {
+			return spec, fmt.Errorf("invalid package.json: %w", err)
+		}
+		has := func(dep string) bool {
+			_, ok := pkg.Dependencies[dep]
+			_, dev := pkg.DevDependencies[dep]
+			return ok || dev
+		}
+		if !has("express") {
+			return spec, errors.New("package.json does not depend on express; only Express projects are supported")
+		}
+		spec.Language, spec.Framework = "node", "express"
+		spec.TypeScript = exists("tsconfig.json") || has("typescript")
+		spec.Database = "mongodb"
+		switch {
+		case has("drizzle-orm"):
+			spec.ORM = "drizzle"
+			spec.Database = pick(has, [][2]string{{"mysql2", "mysql"}}, "postgres")
+		case has("mongoose"):
+			spec.ORM = "mongoose"
+		}
+	case exists("requirements.txt"):
+		reqs := strings.ToLower(read("requirements.txt"))
+		has := func(dep string) bool {
+			return regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(dep) + `\b`).MatchString(reqs)
+		}
+		spec.Language = "python"
+		switch {
+		case has("fastapi"):
+			spec.Framework = "fastapi"
+		case has("flask"):
+			spec.Framework = "flask"
+		default:
+			return spec, errors.New("requirements.txt lists neither fastapi nor flask")
+		}
+		spec.Database = pick(has, [][2]string{{"psycopg2", "postgres"}, {"psycopg", "postgres"}, {"pymysql", "mysql"}, {"mysqlclient", "mysql"}}, "sqlite")
+	default:
+		return spec, fmt.Errorf("%s is not a backendforger project: it has no %s, go.mod, package.json or requirements.txt", dir, LockFileName)
+	}
+	return spec, nil
+}
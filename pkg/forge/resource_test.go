@@ -0,0 +1,214 @@
+package forge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Field
+		err  string
+	}{
+		{in: "name:string,price:float", want: []Field{{"name", "string"}, {"price", "float"}}},
+		{in: " name , stock : INT ,", want: []Field{{"name", "string"}, {"stock", "int"}}},
+		{in: "shipped_at:datetime,note:text,active:bool", want: []Field{{"shipped_at", "datetime"}, {"note", "text"}, {"active", "bool"}}},
+		{in: "", err: "at least one field"},
+		{in: " , ", err: "at least one field"},
+		{in: "Name:string", err: `invalid field name "Name"`},
+		{in: "2nd:int", err: `invalid field name "2nd"`},
+		{in: "id:int", err: `field "id" is generated`},
+		{in: "class:string", err: "Python keyword"},
+		{in: "price:money", err: `unsupported field type "money"`},
+		{in: "price:flaot", err: `did you mean "float"?`},
+		{in: "name,name:text", err: `field "name" is given twice`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseFields(tt.in)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseFields(%q) error = %v, want %q", tt.in, err, tt.err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFields(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckResourceChecksFields(t *testing.T) {
+	tests := []struct {
+		fields []Field
+		err    string
+	}{
+		{[]Field{{"name", "string"}, {"price", "float"}}, ""},
+		{[]Field{{"price", "money"}}, `unsupported field type "money"`},
+		{[]Field{{"Price", "float"}}, `invalid field name "Price"`},
+		{[]Field{{"id", "int"}}, `field "id" is generated`},
+		{[]Field{{"name", "string"}, {"name", "text"}}, `field "name" is given twice`},
+	}
+	for _, tt := range tests {
+		err := checkResource(Resource{Name: "product", Fields: tt.fields})
+		if tt.err == "" && err != nil || tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("checkResource(%v) = %v, want %q", tt.fields, err, tt.err)
+		}
+	}
+}
+
+func TestFieldTypeOfUnknownType(t *testing.T) {
+	if typ, err := (Field{Name: "price", Type: "money"}).GoType(); err == nil {
+		t.Errorf("GoType() = %q, want an error", typ)
+	}
+	if typ, err := (Field{Name: "price", Type: "float"}).GoType(); err != nil || typ != "float64" {
+		t.Errorf("GoType() = %q, %v; want float64", typ, err)
+	}
+}
+
+func TestPlural(t *testing.T) {
+	tests := map[string]string{
+		"product":    "products",
+		"order_item": "order_items",
+		"bus":        "buses",
+		"box":        "boxes",
+		"batch":      "batches",
+		"wish":       "wishes",
+		"category":   "categories",
+		"day":        "days",
+		"y":          "ys",
+	}
+	for in, want := range tests {
+		if got := plural(in); got != want {
+			t.Errorf("plural(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestApplyEdit(t *testing.T) {
+	const goMain = "package main\n\nimport (\n\t\"net/http\"\n)\n\nfunc main() {\n\tmux := http.NewServeMux()\n\thttp.ListenAndServe(\":8080\", mux)\n}\n"
+	register := Edit{
+		File:   "main.go",
+		Vars:   map[string]string{"mux": `http\.ListenAndServe\([^,]+,\s*(\w+)\)`},
+		Before: []string{`http\.ListenAndServe`},
+		InFunc: true,
+		Insert: "controllers.Register[[ pascal .Resource.Name ]]Routes([[ .Vars.mux ]])",
+	}
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		edit    Edit
+		status  string
+		want    string
+		snippet string
+	}{
+		{
+			name: "after imports", file: "main.go", content: goMain,
+			edit:   Edit{File: "main.go", After: []string{`^import \($`}, Insert: "\t\"shop/controllers\""},
+			status: EditApplied,
+			want:   strings.Replace(goMain, "import (\n", "import (\n\t\"shop/controllers\"\n", 1),
+		},
+		{
+			name: "before call with var", file: "main.go", content: goMain, edit: register,
+			status: EditApplied,
+			want:   strings.Replace(goMain, "\thttp.ListenAndServe", "\tcontrollers.RegisterOrderItemRoutes(mux)\n\thttp.ListenAndServe", 1),
+		},
+		{
+			name: "already present", file: "main.go", edit: register,
+			content: strings.Replace(goMain, "\thttp.ListenAndServe", "\tcontrollers.RegisterOrderItemRoutes(mux)\n\thttp.ListenAndServe", 1),
+			status:  EditPresent,
+		},
+		{
+			name: "var not found", file: "main.go", edit: register,
+			content: strings.Replace(goMain, `":8080", mux`, `":8080", nil, extra`, 1),
+			status:  EditManual, snippet: "controllers.RegisterOrderItemRoutes(<mux>)",
+		},
+		{
+			name: "one-line import", file: "main.go", content: "package main\n\nimport \"net/http\"\n",
+			edit:   Edit{File: "main.go", After: []string{`^import \($`}, Insert: "\t\"shop/controllers\""},
+			status: EditManual, snippet: "\t\"shop/controllers\"",
+		},
+		{
+			name: "anchor outside a function", file: "main.go", edit: register,
+			content: "package main\n\nimport \"net/http\"\n\nfunc main() { http.ListenAndServe(\":8080\", mux) }\n",
+			status:  EditManual, snippet: "controllers.RegisterOrderItemRoutes(mux)",
+		},
+		{
+			name: "anchor in a comment before the function", file: "main.go", edit: register,
+			content: "package main\n\n// http.ListenAndServe(addr, mux) serves it.\nfunc main() {\n\thttp.ListenAndServe(\":8080\", mux)\n}\n",
+			status:  EditApplied,
+			want:    "package main\n\n// http.ListenAndServe(addr, mux) serves it.\nfunc main() {\n\tcontrollers.RegisterOrderItemRoutes(mux)\n\thttp.ListenAndServe(\":8080\", mux)\n}\n",
+		},
+		{
+			name: "after a run of imports", file: "app/main.py",
+			content: "from fastapi import FastAPI\nfrom .users.router import router\n\napp = FastAPI()\n",
+			edit:    Edit{File: "app/main.py", After: []string{`^from `}, Insert: "from .[[ plural .Resource.Name ]].router import router as [[ .Resource.Name ]]_router"},
+			status:  EditApplied,
+			want:    "from fastapi import FastAPI\nfrom .users.router import router\nfrom .order_items.router import router as order_item_router\n\napp = FastAPI()\n",
+		},
+		{
+			name: "missing file", file: "src/index.js",
+			edit:   Edit{File: "src/index.js", Before: []string{`\.listen\(`}, Insert: "app.use(x)"},
+			status: EditManual, snippet: "app.use(x)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.content != "" {
+				path := filepath.Join(dir, filepath.FromSlash(tt.file))
+				os.MkdirAll(filepath.Dir(path), 0755)
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			data := ResourceContext{RenderContext: newRenderContext(Spec{Language: "go", Name: "shop"}), Resource: Resource{Name: "order_item"}}
+			edit, content, err := applyEdit(dir, tt.edit, data, DefaultDelimiters, map[string]string{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if edit.Status != tt.status {
+				t.Fatalf("status = %s, want %s (snippet %q)", edit.Status, tt.status, edit.Snippet)
+			}
+			switch tt.status {
+			case EditApplied:
+				if content != tt.want {
+					t.Errorf("content:\n%s\nwant:\n%s", content, tt.want)
+				}
+			case EditManual:
+				if edit.Snippet != tt.snippet || content != tt.content {
+					t.Errorf("snippet = %q, want %q; content changed: %v", edit.Snippet, tt.snippet, content != tt.content)
+				}
+			}
+		})
+	}
+}
+
+func TestAddResourceLeavesFileAloneWhenAnEditIsManual(t *testing.T) {
+	dir := t.TempDir()
+	main := "package main\n\nimport \"net/http\"\n\nfunc main() {\n\tmux := http.NewServeMux()\n\thttp.ListenAndServe(\":8080\", mux)\n}\n"
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/shop\n\ngo 1.22\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte(main), 0644)
+
+	g := &Generator{Source: NewEmbedSource()}
+	fields := []Field{{"name", "string"}}
+	result, err := g.AddResource(context.Background(), dir, Resource{Name: "product", Fields: fields}, AddResourceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range result.Edits {
+		if e.Status != EditManual || e.Snippet == "" {
+			t.Errorf("edit of %s is %s with snippet %q, want manual with a snippet", e.File, e.Status, e.Snippet)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "main.go")); string(data) != main {
+		t.Errorf("main.go was changed:\n%s", data)
+	}
+}
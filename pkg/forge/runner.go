@@ -0,0 +1,208 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Invocation is one run of an external command.
+type Invocation struct {
+	// Args is the program followed by its arguments.
+	Args []string
+	// Dir is the working directory; "" means the current one.
+	Dir string
+	// Env holds KEY=VALUE pairs added to the inherited environment.
+	Env []string
+	// Timeout, if positive, kills the command once it has run this long.
+	Timeout time.Duration
+	// Output receives the combined stdout and stderr. Nil discards it.
+	Output io.Writer
+}
+
+// String returns the command line, for logs and error messages.
+func (inv Invocation) String() string {
+	return strings.Join(inv.Args, " ")
+}
+
+// CommandRunner runs external commands. Implementations must stop the
+// command when ctx is done.
+type CommandRunner interface {
+	Run(ctx context.Context, inv Invocation) error
+}
+
+// ExecRunner runs commands with os/exec.
+type ExecRunner struct {
+	// Env holds KEY=VALUE pairs added to the environment of every command,
+	// before the invocation's own.
+	Env []string
+}
+
+func (r ExecRunner) Run(ctx context.Context, inv Invocation) error {
+	if len(inv.Args) == 0 {
+		return errors.New("empty command")
+	}
+	if inv.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, inv.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, inv.Args[0], inv.Args[1:]...)
+	cmd.Dir = inv.Dir
+	if len(r.Env) > 0 || len(inv.Env) > 0 {
+		cmd.Env = slices.Concat(os.Environ(), r.Env, inv.Env)
+	}
+	cmd.Stdout = inv.Output
+	cmd.Stderr = inv.Output
+	// Once the command is killed, stop waiting for output pipes still held
+	// open by its children (npm spawns many).
+	cmd.WaitDelay = 5 * time.Second
+
+	err := cmd.Run()
+	if inv.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", inv.Timeout, err)
+	}
+	return err
+}
+
+// PathLooker is implemented by runners that can tell where the program
+// they would run for bin is, so missing tools are reported before anything
+// is written.
+type PathLooker interface {
+	LookPath(bin string) (string, error)
+}
+
+// LookPath looks bin up in PATH.
+func (ExecRunner) LookPath(bin string) (string, error) {
+	return exec.LookPath(bin)
+}
+
+// LookPath asks Runner, or PATH if Runner is nil.
+func (r LoggingRunner) LookPath(bin string) (string, error) {
+	if r.Runner == nil {
+		return exec.LookPath(bin)
+	}
+	return lookPath(r.Runner, bin)
+}
+
+// lookPath returns where runner finds bin. Runners that are no PathLooker
+// are assumed to find every program and report their own errors.
+func lookPath(runner CommandRunner, bin string) (string, error) {
+	if l, ok := runner.(PathLooker); ok {
+		return l.LookPath(bin)
+	}
+	return bin, nil
+}
+
+// binaryAliases are tried in order when a program is not found; many
+// systems only install Python as python3.
+var binaryAliases = map[string][]string{"python": {"python3"}}
+
+// resolveBinary returns bin, or the first of its aliases runner finds if it
+// does not find bin itself.
+func resolveBinary(runner CommandRunner, bin string) string {
+	if _, err := lookPath(runner, bin); err == nil {
+		return bin
+	}
+	for _, alias := range binaryAliases[bin] {
+		if _, err := lookPath(runner, alias); err == nil {
+			return alias
+		}
+	}
+	return bin
+}
+
+// LoggingRunner logs every command, its directory, duration and outcome
+// to Log before passing it on to Runner.
+type LoggingRunner struct {
+	Runner CommandRunner
+	Log    io.Writer
+}
+
+func (r LoggingRunner) Run(ctx context.Context, inv Invocation) error {
+	dir := inv.Dir
+	if dir == "" {
+		dir = "."
+	}
+	env := ""
+	if len(inv.Env) > 0 {
+		env = strings.Join(inv.Env, " ") + " "
+	}
+	fmt.Fprintf(r.Log, "+ (cd %s && %s%s)\n", dir, env, inv)
+
+	start := time.Now()
+	runner := r.Runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	err := runner.Run(ctx, inv)
+	took := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(r.Log, "  %s failed after %s: %v\n", inv.Args[0], took, err)
+	} else {
+		fmt.Fprintf(r.Log, "  %s finished in %s\n", inv.Args[0], took)
+	}
+	return err
+}
+
+// RecordingRunner records invocations instead of running anything. It lets
+// tests and dry runs check which commands a generation would run.
+type RecordingRunner struct {
+	// Respond, if set, returns the output and error of an invocation.
+	Respond func(inv Invocation) (output string, err error)
+	// Missing lists the programs LookPath does not find; every other one
+	// is found at its bare name.
+	Missing []string
+
+	mu          sync.Mutex
+	invocations []Invocation
+}
+
+func (r *RecordingRunner) Run(ctx context.Context, inv Invocation) error {
+	r.mu.Lock()
+	r.invocations = append(r.invocations, inv)
+	r.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r.Respond == nil {
+		return nil
+	}
+	output, err := r.Respond(inv)
+	if inv.Output != nil && output != "" {
+		io.WriteString(inv.Output, output)
+	}
+	return err
+}
+
+// LookPath reports bin as not found if it is in Missing.
+func (r *RecordingRunner) LookPath(bin string) (string, error) {
+	if slices.Contains(r.Missing, bin) {
+		return "", &exec.Error{Name: bin, Err: exec.ErrNotFound}
+	}
+	return bin, nil
+}
+
+// Invocations returns the recorded invocations in order.
+func (r *RecordingRunner) Invocations() []Invocation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.invocations)
+}
+
+// Commands returns the command line of every recorded invocation.
+func (r *RecordingRunner) Commands() []string {
+	var commands []string
+	for _, inv := range r.Invocations() {
+		commands = append(commands, inv.String())
+	}
+	return commands
+}
@@ -0,0 +1,72 @@
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/joho/godotenv"
+)
+
+// DefaultRegion is used when neither the flags, the config file nor the
+// AWS environment name a region.
+const DefaultRegion = "ap-south-1"
+
+// newS3Client builds an S3 client using the standard AWS credential chain:
+// environment variables, the shared config/credentials files (AWS_PROFILE)
+// and instance or container roles. The legacy AWS_ACCESS_ID/AWS_SECRET_KEY
+// pair, optionally loaded from a .env file, is still honoured.
+func newS3Client(region string) (*s3.S3, error) {
+	// A .env file is optional; it only pre-populates the environment.
+	_ = godotenv.Load()
+
+	cfg := aws.Config{}
+	if region != "" {
+		cfg.Region = aws.String(region)
+	}
+	if id, secret := os.Getenv("AWS_ACCESS_ID"), os.Getenv("AWS_SECRET_KEY"); id != "" || secret != "" {
+		if id == "" {
+			return nil, errors.New("AWS_SECRET_KEY is set but AWS_ACCESS_ID is missing")
+		}
+		if secret == "" {
+			return nil, errors.New("AWS_ACCESS_ID is set but AWS_SECRET_KEY is missing")
+		}
+		cfg.Credentials = credentials.NewStaticCredentials(id, secret, "")
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            cfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating AWS session: %w", err)
+	}
+	if aws.StringValue(sess.Config.Region) == "" {
+		sess.Config.Region = aws.String(DefaultRegion)
+	}
+
+	if _, err := sess.Config.Credentials.Get(); err != nil {
+		return nil, missingCredentialsError(err)
+	}
+	return s3.New(sess), nil
+}
+
+// missingCredentialsError names the credential that is missing so the user
+// knows what to set instead of getting a raw SDK error.
+func missingCredentialsError(err error) error {
+	id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	switch {
+	case id != "" && secret == "":
+		return errors.New("AWS_ACCESS_KEY_ID is set but AWS_SECRET_ACCESS_KEY is missing")
+	case id == "" && secret != "":
+		return errors.New("AWS_SECRET_ACCESS_KEY is set but AWS_ACCESS_KEY_ID is missing")
+	case os.Getenv("AWS_PROFILE") != "":
+		return fmt.Errorf("no credentials found for AWS profile %q: %w", os.Getenv("AWS_PROFILE"), err)
+	}
+	return errors.New("no AWS credentials found: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, " +
+		"select a profile with AWS_PROFILE, or use --template-source with a local directory mirroring the bucket")
+}
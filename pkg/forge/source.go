@@ -0,0 +1,235 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/TheRSTech/Backendforger-backend/templates"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// DefaultBucket is the S3 bucket templates are read from when no other source is configured.
+const DefaultBucket = "backendforger"
+
+// ErrTemplateNotFound is returned by a TemplateSource when a key does not exist.
+var ErrTemplateNotFound = errors.New("template not found")
+
+// TemplateSource provides the raw contents of templates by key,
+// e.g. "templates/go/gin/main.txt". Fetch gives up when ctx is done.
+type TemplateSource interface {
+	Fetch(ctx context.Context, key string) ([]byte, error)
+	String() string
+}
+
+// S3Source reads templates from an S3 bucket. The client is only created
+// when the first template is fetched, so credentials are not needed for
+// commands that never touch S3.
+type S3Source struct {
+	Bucket string
+	Region string
+
+	once      sync.Once
+	client    *s3.S3
+	clientErr error
+}
+
+// NewS3Source returns a source backed by the given bucket. An empty region
+// falls back to the AWS environment and then to DefaultRegion.
+func NewS3Source(bucket, region string) *S3Source {
+	return &S3Source{Bucket: bucket, Region: region}
+}
+
+func (s *S3Source) getClient() (*s3.S3, error) {
+	s.once.Do(func() {
+		s.client, s.clientErr = newS3Client(s.Region)
+	})
+	return s.client, s.clientErr
+}
+
+func (s *S3Source) Fetch(ctx context.Context, key string) ([]byte, error) {
+	data, _, _, err := s.FetchIfNoneMatch(ctx, key, "")
+	return data, err
+}
+
+// FetchIfNoneMatch downloads key unless its ETag still equals etag.
+func (s *S3Source) FetchIfNoneMatch(ctx context.Context, key, etag string) ([]byte, string, bool, error) {
+	client, err := s.getClient()
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	}
+	if etag != "" {
+		input.IfNoneMatch = aws.String(etag)
+	}
+	result, err := client.GetObjectWithContext(ctx, input)
+	if err != nil {
+		var reqErr awserr.RequestFailure
+		if errors.As(err, &reqErr) && reqErr.StatusCode() == http.StatusNotModified {
+			return nil, etag, true, nil
+		}
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, "", false, fmt.Errorf("%w: s3://%s/%s", ErrTemplateNotFound, s.Bucket, key)
+		}
+		return nil, "", false, err
+	}
+	defer result.Body.Close()
+
+	data, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return data, aws.StringValue(result.ETag), false, nil
+}
+
+// Keys lists every object in the bucket under prefix.
+func (s *S3Source) Keys(ctx context.Context, prefix string) ([]string, error) {
+	client, err := s.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	err = client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.Bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			if key := aws.StringValue(obj.Key); !strings.HasSuffix(key, "/") {
+				keys = append(keys, key)
+			}
+		}
+		return true
+	})
+	return keys, err
+}
+
+func (s *S3Source) String() string {
+	return "s3://" + s.Bucket
+}
+
+// DirSource reads templates from a local directory laid out like the bucket,
+// so "templates/go/gin/main.txt" is read from <Root>/templates/go/gin/main.txt.
+type DirSource struct {
+	Root string
+}
+
+// NewDirSource returns a source backed by the directory at root.
+func NewDirSource(root string) (*DirSource, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("template source %s is not a directory", root)
+	}
+	return &DirSource{Root: root}, nil
+}
+
+func (s *DirSource) Fetch(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.Root, filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, filepath.Join(s.Root, filepath.FromSlash(key)))
+	}
+	return data, err
+}
+
+func (s *DirSource) String() string {
+	return s.Root
+}
+
+// EmbedSource reads templates bundled into the binary by the templates
+// package. Unless go generate synced the bucket into it before building, the
+// bundle holds only the index, the manifests and the shared feature and
+// resource templates, which is enough to list, validate and add resources
+// but not to generate projects.
+type EmbedSource struct {
+	FS fs.FS
+}
+
+// NewEmbedSource returns a source backed by the embedded template bundle.
+func NewEmbedSource() *EmbedSource {
+	return &EmbedSource{FS: templates.FS}
+}
+
+func (s *EmbedSource) Fetch(ctx context.Context, key string) ([]byte, error) {
+	data, err := fs.ReadFile(s.FS, strings.TrimPrefix(key, "templates/"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s is not embedded in this binary; use a directory mirroring the bucket, a warmed cache with --offline, or a binary built after 'go generate ./templates'", ErrTemplateNotFound, key)
+	}
+	return data, err
+}
+
+func (s *EmbedSource) String() string {
+	return "embed"
+}
+
+// ParseTemplateSource turns a --template-source value into a TemplateSource.
+// Accepted forms are "s3", "s3://bucket", "embed" and a directory path
+// (optionally prefixed with "dir:"). bucket and region configure the "s3"
+// form; an empty bucket means DefaultBucket.
+func ParseTemplateSource(spec, bucket, region string) (TemplateSource, error) {
+	switch {
+	case spec == "" || spec == "s3":
+		if bucket == "" {
+			bucket = DefaultBucket
+		}
+		return NewS3Source(bucket, region), nil
+	case strings.HasPrefix(spec, "s3://"):
+		bucket := strings.TrimSuffix(strings.TrimPrefix(spec, "s3://"), "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid template source %q: missing bucket name", spec)
+		}
+		return NewS3Source(bucket, region), nil
+	case spec == "embed" || spec == "embedded":
+		return NewEmbedSource(), nil
+	default:
+		return NewDirSource(strings.TrimPrefix(spec, "dir:"))
+	}
+}
+
+// fetchMetadata reads the index or a manifest from src. Both are embedded
+// in the binary and never published to the bucket, so S3 is not contacted
+// for them: listing, validating and planning need neither credentials nor a
+// network.
+func fetchMetadata(ctx context.Context, src TemplateSource, key string) ([]byte, error) {
+	switch src.(type) {
+	case *S3Source, *CachedSource:
+		if data, err := NewEmbedSource().Fetch(ctx, key); err == nil {
+			return data, nil
+		}
+	}
+	return fetchTemplate(ctx, src, key)
+}
+
+// fetchTemplate reads key from src. Keys the source does not have, such as
+// manifests and feature templates not yet published to a bucket, fall back
+// to the copy embedded in the binary; so do keys missing from the cache in
+// offline mode, since the index and manifests are never in the bucket.
+func fetchTemplate(ctx context.Context, src TemplateSource, key string) ([]byte, error) {
+	data, err := src.Fetch(ctx, key)
+	missing := errors.Is(err, ErrTemplateNotFound) || errors.Is(err, ErrNotCached)
+	if _, embedded := src.(*EmbedSource); missing && !embedded {
+		if data, embedErr := NewEmbedSource().Fetch(ctx, key); embedErr == nil {
+			return data, nil
+		}
+	}
+	return data, err
+}
@@ -0,0 +1,33 @@
+package forge
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRemoteSourcesServeEmbeddedMetadata(t *testing.T) {
+	ctx := context.Background()
+	sources := map[string]TemplateSource{
+		// Building a client for this bucket would need credentials.
+		"s3":     NewS3Source("backendforger-test-no-such-bucket", ""),
+		"cached": &CachedSource{Remote: &fakeRemote{offline: true}, Cache: NewTemplateCache(t.TempDir())},
+	}
+	for name, src := range sources {
+		t.Run(name, func(t *testing.T) {
+			r, err := LoadRegistry(ctx, src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Manifest(ctx, "go", "gin"); err != nil {
+				t.Fatal(err)
+			}
+			plan, err := (&Generator{Source: src, Dir: t.TempDir()}).Plan(ctx, Spec{Language: "go", Name: "svc", Framework: "gin"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plan.Files) == 0 {
+				t.Error("the plan has no files")
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package forge
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecFileName is the conventional name of a spec file.
+const SpecFileName = "backendforger.yaml"
+
+// SpecFileVersion is the version of the spec file format written by
+// MarshalSpecFile and the only one ReadSpecFile accepts.
+const SpecFileVersion = 1
+
+// specFile is the on-disk form of a Spec.
+type specFile struct {
+	Version int `yaml:"version"`
+	Spec    `yaml:",inline"`
+}
+
+// ReadSpecFile reads a spec file. Unknown keys are rejected so typos do not
+// silently change what is generated.
+func ReadSpecFile(path string) (Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Spec{}, err
+	}
+	spec, err := ParseSpecFile(data)
+	if err != nil {
+		return Spec{}, fmt.Errorf("invalid spec file %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// ParseSpecFile parses the contents of a spec file.
+func ParseSpecFile(data []byte) (Spec, error) {
+	var f specFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return Spec{}, err
+	}
+	if f.Version != SpecFileVersion {
+		return Spec{}, fmt.Errorf("unsupported spec version %d (expected %d)", f.Version, SpecFileVersion)
+	}
+	return f.Spec, nil
+}
+
+// MarshalSpecFile returns spec in spec file form.
+func MarshalSpecFile(spec Spec) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# Project spec for backendforger. Generate with: backendforger create -f " + SpecFileName + "\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(specFile{Version: SpecFileVersion, Spec: spec}); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
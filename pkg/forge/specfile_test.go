@@ -0,0 +1,51 @@
+package forge
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSpecFileRoundTrip(t *testing.T) {
+	specs := []Spec{
+		{Language: "go", Name: "svc", Framework: "gin"},
+		{Language: "go", Name: "svc", Framework: "gin", Database: "postgres", ORM: "gorm", Features: []string{"docker"}, Module: "example.com/svc", TemplateVersion: "1.2.0"},
+		{Language: "node", Name: "web", Framework: "express", TypeScript: true},
+	}
+	for _, spec := range specs {
+		data, err := MarshalSpecFile(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), SpecFileName)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadSpecFile(path)
+		if err != nil {
+			t.Fatalf("ReadSpecFile of\n%s: %v", data, err)
+		}
+		if !reflect.DeepEqual(got, spec) {
+			t.Errorf("round trip of %+v = %+v\n%s", spec, got, data)
+		}
+	}
+}
+
+func TestParseSpecFileRejects(t *testing.T) {
+	tests := []struct {
+		name, data, err string
+	}{
+		{"unknown key", "version: 1\nlanguage: go\nframework: gin\nframwork: gin\n", `field framwork not found`},
+		{"nested spec", "version: 1\nlanguage: go\nframework: gin\nspec:\n  name: svc\n", `field spec not found`},
+		{"missing version", "language: go\nframework: gin\n", "unsupported spec version 0"},
+		{"newer version", "version: 2\nlanguage: go\nframework: gin\n", "unsupported spec version 2"},
+	}
+	for _, tt := range tests {
+		_, err := ParseSpecFile([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
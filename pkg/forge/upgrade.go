@@ -0,0 +1,308 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Actions recorded for each file of an upgraded project.
+const (
+	UpgradeUnchanged = "unchanged"
+	UpgradeUpdated   = "updated"
+	UpgradeMerged    = "merged"
+	UpgradeConflict  = "conflict"
+	UpgradeAdded     = "added"
+	UpgradeDeleted   = "deleted"
+	UpgradeRemoved   = "removed"
+)
+
+// UpgradeOptions control Generator.Upgrade.
+type UpgradeOptions struct {
+	// Base provides the templates the project was generated from. Without
+	// it they are rendered from the generator's source if it still
+	// provides the version in the lockfile, and else from the templates in
+	// Cache whose hashes the lockfile records. Files whose original
+	// templates are found nowhere are reported as conflicts in full when
+	// both the user and the new templates changed them.
+	Base TemplateSource
+	// Cache, if set, holds templates fetched earlier, by content hash.
+	Cache *TemplateCache
+	// DryRun computes the result without writing anything.
+	DryRun bool
+	// Diffs records the diff of every file the upgrade changes.
+	Diffs bool
+}
+
+// UpgradeResult summarises an upgrade.
+type UpgradeResult struct {
+	ProjectPath string        `json:"project_path"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Files       []UpgradeFile `json:"files"`
+	Conflicts   int           `json:"conflicts"`
+}
+
+// UpgradeFile reports what an upgrade did to one file:
+//   - unchanged: the new templates produce the same file, or the file
+//     already matches them
+//   - updated: the file had not been modified and was replaced
+//   - merged: both the user and the templates changed it, without conflicts
+//   - conflict: conflict markers were written into the file
+//   - added: the new templates produce a file that did not exist
+//   - deleted: the user deleted the file, so it was not recreated
+//   - removed: the new templates no longer produce the file; it was kept
+//
+// NoBase marks a merged or conflicting file whose original template was not
+// found, so the whole file was compared.
+type UpgradeFile struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	NoBase bool   `json:"no_base,omitempty"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+// Upgrade re-renders the project in dir from its lockfile with the
+// generator's current templates and merges the changes into the files,
+// three-way where the user modified them. The lockfile is then updated to
+// the new templates, with the files left with conflict markers marked as
+// such; Upgrade refuses to run again until they are resolved. Commands are
+// not run.
+func (g *Generator) Upgrade(ctx context.Context, dir string, opts UpgradeOptions) (*UpgradeResult, error) {
+	lock, err := ReadLockFile(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s has no %s; only projects generated by this version of backendforger can be upgraded", dir, LockFileName)
+		}
+		return nil, err
+	}
+	if unresolved, err := unresolvedConflicts(dir, lock); err != nil {
+		return nil, err
+	} else if len(unresolved) > 0 {
+		return nil, fmt.Errorf("%s still contain conflict markers from the last upgrade; resolve them first", strings.Join(unresolved, ", "))
+	}
+
+	spec := lock.Spec
+	spec.TemplateVersion = ""
+	newLock, newFiles, err := renderProject(ctx, g.source(), spec)
+	if err != nil {
+		return nil, err
+	}
+	newLock.Generated, newLock.Resources = lock.Generated, lock.Resources
+	baseFiles, err := g.renderBase(ctx, lock, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpgradeResult{ProjectPath: dir, From: lock.Spec.TemplateVersion, To: newLock.Spec.TemplateVersion, Files: []UpgradeFile{}}
+	ours := "yours"
+	theirs := "templates " + result.To
+	writes := map[string][]byte{}
+
+	for _, nf := range newLock.Files {
+		generated := newFiles[nf.Path]
+		old := lock.File(nf.Path)
+		current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(nf.Path)))
+		exists := err == nil
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		file := UpgradeFile{Path: nf.Path}
+		var content []byte
+		switch {
+		case exists && HashContent(current) == nf.Hash:
+			file.Action = UpgradeUnchanged
+		case old != nil && old.Hash == nf.Hash:
+			// The templates did not change this file; keep the user's edits
+			// or deletion.
+			file.Action = UpgradeUnchanged
+		case !exists && old != nil:
+			file.Action = UpgradeDeleted
+		case !exists:
+			file.Action, content = UpgradeAdded, generated
+		case old != nil && !old.Kept && HashContent(current) == old.Hash:
+			// Unmodified since generation. A kept file never was the
+			// templates' output, so it is always merged.
+			file.Action, content = UpgradeUpdated, generated
+		default:
+			// Changed by both sides. The base is what the old templates
+			// rendered; without it, or for a file they did not produce,
+			// every difference conflicts.
+			base := baseFiles[nf.Path]
+			if old == nil || HashContent(base) != old.Hash {
+				base, file.NoBase = nil, true
+			}
+			merged, conflicts := merge3(base, current, generated, ours, theirs)
+			file.Action, content = UpgradeMerged, merged
+			if conflicts > 0 {
+				file.Action = UpgradeConflict
+				result.Conflicts++
+				newLock.File(nf.Path).Conflict = true
+			}
+		}
+		if content != nil {
+			writes[nf.Path] = content
+			if opts.Diffs {
+				file.Diff = unifiedDiff("a/"+nf.Path, "b/"+nf.Path, current, content)
+			}
+		}
+		result.Files = append(result.Files, file)
+	}
+	for _, old := range lock.Files {
+		if newLock.File(old.Path) == nil {
+			result.Files = append(result.Files, UpgradeFile{Path: old.Path, Action: UpgradeRemoved})
+		}
+	}
+
+	if opts.DryRun || (len(writes) == 0 && lock.Spec.TemplateVersion == newLock.Spec.TemplateVersion && slices.Equal(lock.Files, newLock.Files)) {
+		return result, nil
+	}
+	for p, content := range writes {
+		dest := filepath.Join(dir, filepath.FromSlash(p))
+		perm := fs.FileMode(0644)
+		if info, err := os.Stat(dest); err == nil {
+			perm = info.Mode().Perm()
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return result, err
+		}
+		if err := os.WriteFile(dest, content, perm); err != nil {
+			return result, err
+		}
+	}
+	lockData, err := json.MarshalIndent(newLock, "", "  ")
+	if err != nil {
+		return result, err
+	}
+	return result, os.WriteFile(filepath.Join(dir, LockFileName), append(lockData, '\n'), 0644)
+}
+
+// renderBase renders the templates the project was generated from, for
+// three-way merges: opts.Base if given, else the generator's source at the
+// version in the lockfile and the templates in opts.Cache whose hashes the
+// lockfile records. Only the files that render exactly as recorded are
+// returned; the others have no base.
+func (g *Generator) renderBase(ctx context.Context, lock *Lock, opts UpgradeOptions) (map[string][]byte, error) {
+	if opts.Base != nil {
+		_, files, err := renderProject(ctx, opts.Base, lock.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("rendering the original templates: %w", err)
+		}
+		return files, nil
+	}
+
+	type candidate struct {
+		src  TemplateSource
+		spec Spec
+	}
+	candidates := []candidate{{g.source(), lock.Spec}}
+	if opts.Cache != nil {
+		// The current manifest, which need not be at the old version, with
+		// the original templates.
+		spec := lock.Spec
+		spec.TemplateVersion = ""
+		candidates = append(candidates, candidate{&lockedSource{lock: lock, cache: opts.Cache, next: g.source()}, spec})
+	}
+	base := map[string][]byte{}
+	for _, c := range candidates {
+		_, files, err := renderProject(ctx, c.src, c.spec)
+		if err != nil {
+			continue
+		}
+		for p, content := range files {
+			if old := lock.File(p); old != nil && base[p] == nil && HashContent(content) == old.Hash {
+				base[p] = content
+			}
+		}
+	}
+	return base, nil
+}
+
+// lockedSource serves the templates recorded in a lockfile from a
+// TemplateCache by their hashes, so they are found after the source has
+// moved on. Other keys are read from next.
+type lockedSource struct {
+	lock  *Lock
+	cache *TemplateCache
+	next  TemplateSource
+}
+
+func (s *lockedSource) Fetch(ctx context.Context, key string) ([]byte, error) {
+	for _, f := range s.lock.Files {
+		if f.Template == key {
+			if data, err := s.cache.Object(f.TemplateHash); err == nil {
+				return data, nil
+			}
+		}
+	}
+	return s.next.Fetch(ctx, key)
+}
+
+func (s *lockedSource) String() string {
+	return s.next.String()
+}
+
+// unresolvedConflicts lists the files an earlier upgrade left conflict
+// markers in that still contain them.
+func unresolvedConflicts(dir string, lock *Lock) ([]string, error) {
+	var paths []string
+	for _, f := range lock.Files {
+		if !f.Conflict {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(f.Path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hasConflictMarkers(data) {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths, nil
+}
+
+// hasConflictMarkers reports whether data has a line starting a conflict
+// as written by merge3.
+func hasConflictMarkers(data []byte) bool {
+	for _, line := range splitLines(data) {
+		if strings.HasPrefix(line, "<<<<<<< ") {
+			return true
+		}
+	}
+	return false
+}
+
+// renderProject generates spec from src in memory and returns its lock and
+// the contents of every file the lock lists.
+func renderProject(ctx context.Context, src TemplateSource, spec Spec) (*Lock, map[string][]byte, error) {
+	mem := NewMemFS()
+	rg := Generator{Source: src, FS: mem}
+	if _, err := rg.Generate(ctx, spec); err != nil {
+		return nil, nil, err
+	}
+	data, err := fs.ReadFile(mem, LockFileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, nil, err
+	}
+	files := map[string][]byte{}
+	for _, f := range lock.Files {
+		if files[f.Path], err = fs.ReadFile(mem, f.Path); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &lock, files, nil
+}
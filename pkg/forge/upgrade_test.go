@@ -0,0 +1,84 @@
+package forge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// upgradeFixture generates a gin project from a fake bucket holding ginBucket
+// through a cache, then replaces main.txt in the bucket with newMain.
+func upgradeFixture(t *testing.T, newMain string) (g *Generator, dir string, cache *TemplateCache) {
+	t.Helper()
+	objects := map[string]string{}
+	for k, v := range ginBucket {
+		objects[k] = v
+	}
+	cache = NewTemplateCache(t.TempDir())
+	g = &Generator{Source: &CachedSource{Remote: &fakeRemote{objects: objects}, Cache: cache}, Runner: &RecordingRunner{}, Dir: t.TempDir()}
+	if _, err := g.Generate(context.Background(), Spec{Language: "go", Name: "svc", Framework: "gin"}); err != nil {
+		t.Fatal(err)
+	}
+	objects["templates/go/gin/main.txt"] = newMain
+	return g, filepath.Join(g.Dir, "svc"), cache
+}
+
+func editFile(t *testing.T, path string, edit func(string) string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(edit(string(data))), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpgradeMergesWithCachedBase(t *testing.T) {
+	g, dir, cache := upgradeFixture(t, "// Package main starts svc.\npackage main\n\nimport \"yourapp/api\"\n\nfunc main() { api.Hello() }\n")
+	main := filepath.Join(dir, "main.go")
+	editFile(t, main, func(s string) string { return s + "\nfunc init() {}\n" })
+
+	result, err := g.Upgrade(context.Background(), dir, UpgradeOptions{Cache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Conflicts != 0 {
+		t.Fatalf("conflicts = %d, want 0: %+v", result.Conflicts, result.Files)
+	}
+	data, _ := os.ReadFile(main)
+	if !strings.HasPrefix(string(data), "// Package main") || !strings.HasSuffix(string(data), "func init() {}\n") {
+		t.Errorf("main.go lacks one side's change:\n%s", data)
+	}
+}
+
+func TestUpgradeRecordsUnresolvedConflicts(t *testing.T) {
+	g, dir, cache := upgradeFixture(t, "package main\n\nimport \"yourapp/api\"\n\nfunc main() { api.Hello(); api.Hello() }\n")
+	main := filepath.Join(dir, "main.go")
+	editFile(t, main, func(s string) string { return strings.Replace(s, "api.Hello()", "api.Hello() // hi", 1) })
+
+	result, err := g.Upgrade(context.Background(), dir, UpgradeOptions{Cache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Conflicts != 1 {
+		t.Fatalf("conflicts = %d, want 1", result.Conflicts)
+	}
+	lock, err := ReadLockFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := lock.File("main.go"); f == nil || !f.Conflict {
+		t.Fatalf("main.go is not marked as conflicting in the lockfile: %+v", f)
+	}
+
+	if _, err := g.Upgrade(context.Background(), dir, UpgradeOptions{Cache: cache}); err == nil || !strings.Contains(err.Error(), "main.go") {
+		t.Fatalf("upgrade with unresolved conflicts: err = %v", err)
+	}
+	editFile(t, main, func(string) string { return "package main\n\nfunc main() {}\n" })
+	if _, err := g.Upgrade(context.Background(), dir, UpgradeOptions{Cache: cache}); err != nil {
+		t.Fatalf("upgrade after resolving: %v", err)
+	}
+}
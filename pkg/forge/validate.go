@@ -0,0 +1,70 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists every problem found in a Spec.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid project spec: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks spec against the registry of supported combinations
+// without writing anything. Problems are reported as a *ValidationError.
+func (g *Generator) Validate(ctx context.Context, spec Spec) error {
+	_, err := g.validate(ctx, spec)
+	return err
+}
+
+// CheckName checks the project name of spec and the module or package name
+// derived from it, before the rest of the spec is known. Without a language
+// only the directory name is checked. Problems are reported as a
+// *ValidationError.
+func CheckName(spec Spec) error {
+	if problems := checkNames(spec); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validate checks spec and returns the manifest of its framework.
+func (g *Generator) validate(ctx context.Context, spec Spec) (*Manifest, error) {
+	problems := checkNames(spec)
+	if spec.Language == "" {
+		problems = append(problems, "a language is required")
+	}
+	if spec.Framework == "" {
+		problems = append(problems, "a framework is required")
+	}
+	if spec.Language == "" || spec.Framework == "" {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	reg := g.Registry
+	if reg == nil {
+		var err error
+		if reg, err = LoadRegistry(ctx, g.source()); err != nil {
+			return nil, err
+		}
+	}
+	problems = append(problems, reg.Check(ctx, spec)...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	m, err := reg.Manifest(ctx, spec.Language, spec.Framework)
+	if err != nil {
+		return nil, err
+	}
+	if spec.TemplateVersion != "" && spec.TemplateVersion != m.Version {
+		return nil, &ValidationError{Problems: []string{fmt.Sprintf("spec requires %s templates version %q but the source provides %q",
+			spec.Framework, spec.TemplateVersion, m.Version)}}
+	}
+	return m, nil
+}
@@ -0,0 +1,319 @@
+// Package server exposes project generation over HTTP.
+//
+// Every request gets its own Generator writing into memory, so requests
+// never share files and no external commands are run on the server. The
+// generated project is returned as a zip or tar.gz archive.
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+)
+
+// maxSpecBytes limits the size of request bodies.
+const maxSpecBytes = 64 << 10
+
+// Server serves the generation API.
+type Server struct {
+	source      forge.TemplateSource
+	timeout     time.Duration
+	allowOrigin string
+	logger      *log.Logger
+	slots       chan struct{}
+
+	// registry is loaded on the first request that needs it and kept, so
+	// the catalog and manifests are parsed once rather than per request.
+	mu       sync.Mutex
+	registry *forge.Registry
+}
+
+// Options configure a Server.
+type Options struct {
+	// Timeout bounds the handling of a single request, including the wait
+	// for a free generation slot.
+	Timeout time.Duration
+	// MaxConcurrent is the number of projects generated at the same time.
+	MaxConcurrent int
+	// AllowOrigin, if set, is sent as Access-Control-Allow-Origin.
+	AllowOrigin string
+	// Logger receives one line per request. Defaults to log.Default().
+	Logger *log.Logger
+}
+
+// New returns a Server reading templates from src.
+func New(src forge.TemplateSource, opts Options) *Server {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = 1
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.Default()
+	}
+	return &Server{
+		source:      src,
+		timeout:     opts.Timeout,
+		allowOrigin: opts.AllowOrigin,
+		logger:      opts.Logger,
+		slots:       make(chan struct{}, opts.MaxConcurrent),
+	}
+}
+
+// Handler returns the HTTP handler serving:
+//
+//	GET  /healthz
+//	GET  /v1/frameworks?language=
+//	GET  /v1/databases?language=&framework=
+//	GET  /v1/orms?language=&framework=
+//	POST /v1/validate
+//	POST /v1/generate?format=zip|tar.gz
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /v1/frameworks", s.handleFrameworks)
+	mux.HandleFunc("GET /v1/databases", s.handleOptions((*forge.Manifest).Databases))
+	mux.HandleFunc("GET /v1/orms", s.handleOptions((*forge.Manifest).ORMs))
+	mux.HandleFunc("POST /v1/validate", s.handleValidate)
+	mux.HandleFunc("POST /v1/generate", s.handleGenerate)
+	return s.middleware(mux)
+}
+
+// middleware applies the request timeout, CORS headers and logging.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		if s.allowOrigin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", s.allowOrigin)
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+		defer cancel()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+		s.logger.Printf("%s %s %d %v", r.Method, r.URL.Path, sw.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// frameworkInfo is one entry of GET /v1/frameworks.
+type frameworkInfo struct {
+	Language     string              `json:"language"`
+	Framework    string              `json:"framework"`
+	Description  string              `json:"description,omitempty"`
+	Databases    []string            `json:"databases"`
+	ORMs         []string            `json:"orms"`
+	Combinations []forge.Combination `json:"combinations"`
+	Features     []forge.Feature     `json:"features"`
+}
+
+func (s *Server) handleFrameworks(w http.ResponseWriter, r *http.Request) {
+	manifests, err := s.manifests(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	infos := []frameworkInfo{}
+	for _, m := range manifests {
+		infos = append(infos, frameworkInfo{
+			Language:     m.Language,
+			Framework:    m.Framework,
+			Description:  m.Description,
+			Databases:    nonNil(m.Databases()),
+			ORMs:         nonNil(m.ORMs()),
+			Combinations: nonNil(m.Combinations),
+			Features:     nonNil(m.Features),
+		})
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+// handleOptions lists the distinct values field returns for the frameworks
+// matching the query.
+func (s *Server) handleOptions(field func(*forge.Manifest) []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		manifests, err := s.manifests(r)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		values := []string{}
+		for _, m := range manifests {
+			for _, v := range field(m) {
+				if !slices.Contains(values, v) {
+					values = append(values, v)
+				}
+			}
+		}
+		slices.Sort(values)
+		writeJSON(w, http.StatusOK, values)
+	}
+}
+
+// loadRegistry returns the registry of the template source, loading it on
+// first use. A failed load is retried by the next request.
+func (s *Server) loadRegistry(ctx context.Context) (*forge.Registry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.registry == nil {
+		reg, err := forge.LoadRegistry(ctx, s.source)
+		if err != nil {
+			return nil, err
+		}
+		s.registry = reg
+	}
+	return s.registry, nil
+}
+
+// generator returns a Generator for one request, writing into memory.
+func (s *Server) generator(ctx context.Context) (*forge.Generator, error) {
+	reg, err := s.loadRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &forge.Generator{Source: s.source, Registry: reg}, nil
+}
+
+// manifests loads the manifests selected by the language and framework
+// query parameters.
+func (s *Server) manifests(r *http.Request) ([]*forge.Manifest, error) {
+	reg, err := s.loadRegistry(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := reg.Manifests(r.Context(), r.URL.Query().Get("language"))
+	if err != nil {
+		return nil, err
+	}
+	if fw := r.URL.Query().Get("framework"); fw != "" {
+		manifests = slices.DeleteFunc(manifests, func(m *forge.Manifest) bool { return m.Framework != fw })
+	}
+	return manifests, nil
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	spec, ok := readSpec(w, r)
+	if !ok {
+		return
+	}
+	g, err := s.generator(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := g.Validate(r.Context(), spec); err != nil {
+		var verr *forge.ValidationError
+		if errors.As(err, &verr) {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"valid": false, "errors": verr.Problems})
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"valid": true, "errors": []string{}})
+}
+
+func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	format := forge.ArchiveZip
+	if f := r.URL.Query().Get("format"); f != "" {
+		var err error
+		if format, err = forge.ParseArchiveFormat(f); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	spec, ok := readSpec(w, r)
+	if !ok {
+		return
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-r.Context().Done():
+		writeError(w, http.StatusServiceUnavailable, errors.New("server busy, try again later"))
+		return
+	}
+
+	// Archive into memory first so failures still get a proper status.
+	var buf bytes.Buffer
+	g, err := s.generator(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := g.Archive(r.Context(), spec, &buf, format, false); err != nil {
+		var verr *forge.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"valid": false, "errors": verr.Problems})
+		case r.Context().Err() != nil:
+			writeError(w, http.StatusGatewayTimeout, errors.New("generation timed out"))
+		default:
+			writeError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", spec.Name+"."+string(format)))
+	w.Header().Set("Content-Length", fmt.Sprint(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	buf.WriteTo(w)
+}
+
+// readSpec decodes the request body, writing an error response if it is invalid.
+func readSpec(w http.ResponseWriter, r *http.Request) (forge.Spec, bool) {
+	var spec forge.Spec
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSpecBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid spec: %w", err))
+		return spec, false
+	}
+	return spec, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// statusWriter records the status code for logging.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
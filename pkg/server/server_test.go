@@ -0,0 +1,141 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+)
+
+// stubSource serves the embedded index and manifests, counting reads of
+// the index, and a one-line file for every project template.
+type stubSource struct {
+	indexReads atomic.Int32
+}
+
+func (s *stubSource) Fetch(ctx context.Context, key string) ([]byte, error) {
+	if key == "templates/index.json" {
+		s.indexReads.Add(1)
+	}
+	data, err := forge.NewEmbedSource().Fetch(ctx, key)
+	if errors.Is(err, forge.ErrTemplateNotFound) {
+		return []byte("// " + key + "\n"), nil
+	}
+	return data, err
+}
+
+func (s *stubSource) String() string { return "stub" }
+
+func newTestServer(t *testing.T) (*httptest.Server, *stubSource) {
+	t.Helper()
+	src := &stubSource{}
+	ts := httptest.NewServer(New(src, Options{Logger: log.New(io.Discard, "", 0)}).Handler())
+	t.Cleanup(ts.Close)
+	return ts, src
+}
+
+func post(t *testing.T, url, body string) (*http.Response, []byte) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, data
+}
+
+func TestValidate(t *testing.T) {
+	ts, _ := newTestServer(t)
+	tests := []struct {
+		body   string
+		status int
+		want   string
+	}{
+		{`{"language": "go", "name": "svc", "framework": "gin"}`, http.StatusOK, `{"errors":[],"valid":true}`},
+		{`{"language": "go", "name": "svc", "framework": "gni"}`, http.StatusUnprocessableEntity, `did you mean \"gin\"?`},
+		{`{"language": "go", "name": "svc", "framework": "gin", "colour": "red"}`, http.StatusBadRequest, `unknown field \"colour\"`},
+		{`{"language": "go"`, http.StatusBadRequest, `"error":"invalid spec`},
+	}
+	for _, tt := range tests {
+		resp, body := post(t, ts.URL+"/v1/validate", tt.body)
+		if resp.StatusCode != tt.status || !strings.Contains(string(body), tt.want) {
+			t.Errorf("POST /v1/validate %s = %d %s, want %d with %s", tt.body, resp.StatusCode, body, tt.status, tt.want)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("POST /v1/validate %s: Content-Type = %q", tt.body, ct)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ts, src := newTestServer(t)
+	spec := `{"language": "go", "name": "svc", "framework": "gin"}`
+
+	resp, body := post(t, ts.URL+"/v1/generate", spec)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /v1/generate = %d %s", resp.StatusCode, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("Content-Type = %q, want application/zip", ct)
+	}
+	if cd := resp.Header.Get("Content-Disposition"); cd != `attachment; filename="svc.zip"` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	if !slices.Contains(names, "svc/main.go") {
+		t.Errorf("archive entries = %v, want svc/main.go", names)
+	}
+
+	resp, _ = post(t, ts.URL+"/v1/generate?format=tar.gz", spec)
+	if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || ct != "application/gzip" {
+		t.Errorf("tar.gz: %d with Content-Type %q", resp.StatusCode, ct)
+	}
+	if n := src.indexReads.Load(); n != 1 {
+		t.Errorf("the index was read %d times, want once for the server", n)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	ts, _ := newTestServer(t)
+	tests := []struct {
+		query, body string
+		status      int
+		want        string
+	}{
+		{"?format=rar", `{"language": "go", "name": "svc", "framework": "gin"}`, http.StatusBadRequest, "rar"},
+		{"", `{"language": "go", "name": "my app", "framework": "gin"}`, http.StatusUnprocessableEntity, `"valid":false`},
+		{"", `not json`, http.StatusBadRequest, "invalid spec"},
+	}
+	for _, tt := range tests {
+		resp, body := post(t, ts.URL+"/v1/generate"+tt.query, tt.body)
+		var out map[string]any
+		if err := json.Unmarshal(body, &out); err != nil {
+			t.Errorf("POST /v1/generate%s %s: body is not JSON: %s", tt.query, tt.body, body)
+		}
+		if resp.StatusCode != tt.status || !strings.Contains(string(body), tt.want) {
+			t.Errorf("POST /v1/generate%s %s = %d %s, want %d with %s", tt.query, tt.body, resp.StatusCode, body, tt.status, tt.want)
+		}
+	}
+}
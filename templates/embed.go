@@ -0,0 +1,16 @@
+// Package templates bundles part of the template tree into the binary: the
+// framework index, every manifest and the templates shared by all packs
+// (features and resources). The per-framework project templates live in the
+// S3 bucket; go generate syncs them into this directory, and building after
+// that produces a binary that can generate projects without network access.
+package templates
+
+import "embed"
+
+//go:generate go run ./sync
+
+// FS holds the embedded templates. Paths mirror the "templates/" prefix of
+// the S3 bucket, e.g. "go/gin/manifest.json".
+//
+//go:embed index.json all:go all:node all:python
+var FS embed.FS
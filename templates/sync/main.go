@@ -0,0 +1,81 @@
+// Command sync downloads the project templates referenced by the embedded
+// manifests into the templates directory, so that the next build embeds a
+// complete bundle and --template-source embed can generate projects without
+// network access. It is run by go generate in the templates package:
+//
+//	go generate ./templates && go build
+//
+// Templates are read from the bucket by default; -source accepts every
+// --template-source value, e.g. a directory mirroring the bucket. Templates
+// already in the directory are kept; 'git clean -X templates' removes the
+// synced ones, which are ignored by git.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TheRSTech/Backendforger-backend/pkg/forge"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "The templates directory to fill")
+	source := flag.String("source", "s3", "Where to read templates from, as for --template-source")
+	bucket := flag.String("s3-bucket", "", "S3 bucket holding the templates (default "+forge.DefaultBucket+")")
+	region := flag.String("s3-region", "", "AWS region of the template bucket")
+	flag.Parse()
+
+	n, err := syncTemplates(context.Background(), *dir, *source, *bucket, *region)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sync:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Synced %d templates into %s\n", n, *dir)
+}
+
+// syncTemplates writes every template of the manifests under dir that dir
+// does not hold yet, read from source. The shared feature and resource
+// templates are checked in and so left alone.
+func syncTemplates(ctx context.Context, dir, source, bucket, region string) (int, error) {
+	src, err := forge.ParseTemplateSource(source, bucket, region)
+	if err != nil {
+		return 0, err
+	}
+	local := &forge.EmbedSource{FS: os.DirFS(dir)}
+	reg, err := forge.LoadRegistry(ctx, local)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, lang := range reg.Catalog().Languages {
+		manifests, err := reg.Manifests(ctx, lang.Name)
+		if err != nil {
+			return n, err
+		}
+		for _, m := range manifests {
+			for _, f := range m.Files {
+				path := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(f.Template, "templates/")))
+				if _, err := local.Fetch(ctx, f.Template); err == nil {
+					continue
+				}
+				data, err := src.Fetch(ctx, f.Template)
+				if err != nil {
+					return n, err
+				}
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					return n, err
+				}
+				if err := os.WriteFile(path, data, 0644); err != nil {
+					return n, err
+				}
+				n++
+			}
+		}
+	}
+	return n, nil
+}